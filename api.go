@@ -4,9 +4,12 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"math"
+	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
+	"unicode/utf8"
 )
 
 // Issue represents a finding of duplicated strings, numbers, or constants.
@@ -15,20 +18,69 @@ import (
 // When both test and non-test files are analyzed, OccurrencesCount reflects
 // the count within the issue's scope (test or non-test) rather than the global total.
 type Issue struct {
-	Pos              token.Position
-	OccurrencesCount int
-	Str              string
-	MatchingConst    string
-	DuplicateConst   string
-	DuplicatePos     token.Position
+	Pos              token.Position `json:"pos"`
+	OccurrencesCount int            `json:"occurrences_count"`
+	Str              string         `json:"str"`
+	MatchingConst    string         `json:"matching_const,omitempty"`
+	DuplicateConst   string         `json:"duplicate_const,omitempty"`
+	DuplicatePos     token.Position `json:"duplicate_pos"`
+	// Occurrences lists every position of the string within the issue's
+	// scope, sorted by position. It is shared by the issues of that scope.
+	Occurrences []ExtendedPos `json:"occurrences,omitempty"`
+	// Score ranks the issue by how worthwhile it is to fix. It grows with the
+	// number of occurrences, the length of the literal and the number of
+	// files and packages involved, and is halved in test code.
+	Score float64 `json:"score"`
+	// Category is the kind of literal according to the classifier (see Classify)
+	Category string `json:"category"`
+	// TableTest is set when the occurrences are cases of table-driven tests,
+	// counted apart with TableTestsSeparate
+	TableTest bool `json:"table_test,omitempty"`
+	// Kind is the detector that reported the issue, empty for repeated
+	// strings and duplicate constants
+	Kind string `json:"kind,omitempty"`
+	// Suggestion describes how to fix the issues of the detectors
+	Suggestion string `json:"suggestion,omitempty"`
+	// Values are the literals a KindEnum subject is compared with, Str
+	// being the first of them
+	Values []string `json:"values,omitempty"`
+	// Subject is the variable, struct field or named type of a KindEnum
+	// issue, e.g. "User.Status"
+	Subject string `json:"subject,omitempty"`
 }
 
+// Kinds of the issues reported by the opt-in detectors.
+const (
+	// KindSentinelError is an error message created in several places,
+	// see Parser.SetSentinelErrors
+	KindSentinelError = "sentinel-error"
+	// KindErrorComparison is a comparison of err.Error() with a literal
+	KindErrorComparison = "error-comparison"
+	// KindConfigKey is a configuration key read in several places, see
+	// Parser.SetConfigKeys
+	KindConfigKey = "config-key"
+	// KindUndefinedConfigKey is a configuration key read but never defined
+	// or documented
+	KindUndefinedConfigKey = "undefined-config-key"
+	// KindContextKey is a literal used as a context value key, see
+	// Parser.SetContextKeys
+	KindContextKey = "context-key"
+	// KindEnum is a value compared with a set of literals in several places,
+	// see Parser.SetEnums
+	KindEnum = "enum"
+)
+
 // Config contains all configuration options for the goconst analyzer.
 type Config struct {
 	// IgnoreStrings is a list of regular expressions to filter strings
 	IgnoreStrings []string
 	// IgnoreTests indicates whether test files should be excluded
 	IgnoreTests bool
+	// TestFilePatterns and TestPackagePatterns classify more files as test
+	// code than the "_test.go" files (e.g. "*_mock.go", "internal/testutil",
+	// "fakes"). See Parser.SetTestPatterns.
+	TestFilePatterns    []string
+	TestPackagePatterns []string
 	// MatchWithConstants enables matching strings with existing constants
 	MatchWithConstants bool
 	// MinStringLength is the minimum length a string must have to be reported
@@ -43,12 +95,67 @@ type Config struct {
 	NumberMax int
 	// ExcludeTypes allows excluding specific types of contexts
 	ExcludeTypes map[Type]bool
+	// MinOccurrencesByType overrides MinOccurrences for literals found in the
+	// given contexts. A literal found in several contexts is reported once it
+	// reaches the highest of their thresholds.
+	MinOccurrencesByType map[Type]int
+	// MinLengthByType overrides MinStringLength for literals found in the given contexts
+	MinLengthByType map[Type]int
+	// Scope is the part of the code occurrences are counted in, ScopeModule by default
+	Scope Scope
+	// TestConstants reports the literals of test files that equal a production
+	// constant the test could use, even below MinOccurrences
+	TestConstants bool
+	// SentinelErrors reports repeated error messages and comparisons of
+	// err.Error() with literals (see Parser.SetSentinelErrors)
+	SentinelErrors bool
+	// ConfigKeys reports the configuration keys read in several places, or
+	// never defined nor documented. ConfigKeyReaders and ConfigKeyDefiners
+	// name the accessors of a configuration library, ConfigKeyDocs the files
+	// documenting the keys (see Parser.SetConfigKeys).
+	ConfigKeys        bool
+	ConfigKeyReaders  []string
+	ConfigKeyDefiners []string
+	ConfigKeyDocs     []string
+	// ContextKeys reports every literal used as a context value key (see
+	// Parser.SetContextKeys)
+	ContextKeys bool
+	// Enums reports the variables, fields and named types compared with a
+	// set of literals in several places (see Parser.SetEnums)
+	Enums bool
+	// TableTests is how the cases of table-driven tests are counted, with the
+	// other test literals by default (see Parser.SetTableTests)
+	TableTests TableTestMode
+	// MinFiles is the minimum number of distinct files a literal must appear in within its scope
+	MinFiles int
+	// MinPackages is the minimum number of distinct packages a literal must appear in within its scope
+	MinPackages int
+	// IgnoreInFunctions skips the bodies of the functions matching these
+	// path.Match patterns, matched against "Name" or "Recv.Name" for methods
+	// (e.g. "Test*", "init", "Server.*").
+	IgnoreInFunctions []string
+	// IgnoreComposites skips the literals found in composite literals of these
+	// types, or only in some of their fields (e.g. "cobra.Command{Use,Short}").
+	IgnoreComposites []string
+	// IncludeComposites reports the literals found in composite literals of these
+	// types or fields even when they would be ignored (e.g. "Config{Endpoint}").
+	// See Parser.SetCompositeRules.
+	IncludeComposites []string
+	// CategoryPatterns extends the classifier with regular expressions per category
+	CategoryPatterns map[string][]string
+	// IgnoreCategories drops the issues of these categories
+	IgnoreCategories []string
+	// OnlyCategories keeps only the issues of these categories when not empty
+	OnlyCategories []string
 	// FindDuplicates enables finding constants whose values match existing constants in other packages.
 	FindDuplicates bool
 	// EvalConstExpressions enables evaluation of constant expressions like Prefix + "suffix"
 	EvalConstExpressions bool
 	// IgnoreFunctions is a list of function names whose string arguments should be ignored.
-	// Supports direct calls (e.g., "println") and one-level qualified calls (e.g., "slog.Info").
+	// Names match the call as written (e.g., "println", "slog.Info") or, using typeInfo,
+	// the resolved callee (e.g., "log/slog.Info", "(*testing.T).Errorf"), and may
+	// contain wildcards (e.g., "fmt.*"). A "#" suffix only ignores some arguments
+	// (e.g., "fmt.Errorf#0", "slog.Info#msg", "slog.Info#..."). See Parser.SetIgnoreFunctions.
 	IgnoreFunctions []string
 }
 
@@ -116,15 +223,32 @@ func RunWithConfig(files []*ast.File, fset *token.FileSet, typeInfo *types.Info,
 	if len(cfg.IgnoreFunctions) > 0 {
 		p.SetIgnoreFunctions(cfg.IgnoreFunctions)
 	}
-
-	// Pre-allocate slice based on estimated result size
-	expectedIssues := len(files) * 5 // Assuming average of 5 issues per file
-	if expectedIssues > 1000 {
-		expectedIssues = 1000 // Cap at reasonable maximum
+	p.SetMinOccurrencesByType(cfg.MinOccurrencesByType)
+	p.SetMinLengthByType(cfg.MinLengthByType)
+	p.SetScope(cfg.Scope)
+	p.SetTestConstants(cfg.TestConstants)
+	p.SetSentinelErrors(cfg.SentinelErrors)
+	p.SetConfigKeys(cfg.ConfigKeys, cfg.ConfigKeyReaders, cfg.ConfigKeyDefiners)
+	p.SetContextKeys(cfg.ContextKeys)
+	p.SetEnums(cfg.Enums)
+	p.SetTableTests(cfg.TableTests)
+	p.SetMinSpread(cfg.MinFiles, cfg.MinPackages)
+	p.SetCategoryFilters(cfg.IgnoreCategories, cfg.OnlyCategories)
+	if err := p.SetIgnoreInFunctions(cfg.IgnoreInFunctions); err != nil {
+		return nil, err
+	}
+	if err := p.SetCategoryPatterns(cfg.CategoryPatterns); err != nil {
+		return nil, err
+	}
+	if err := p.SetCompositeRules(cfg.IgnoreComposites, cfg.IncludeComposites); err != nil {
+		return nil, err
+	}
+	if err := p.SetTestPatterns(cfg.TestFilePatterns, cfg.TestPackagePatterns); err != nil {
+		return nil, err
+	}
+	if err := p.SetConfigKeyDocs(cfg.ConfigKeyDocs); err != nil {
+		return nil, err
 	}
-
-	// Allocate a new buffer
-	issueBuffer := make([]Issue, 0, expectedIssues)
 
 	// Process files concurrently
 	var wg sync.WaitGroup
@@ -136,7 +260,7 @@ func RunWithConfig(files []*ast.File, fset *token.FileSet, typeInfo *types.Info,
 	// Filter test files first if needed
 	for _, f := range files {
 		if p.ignoreTests {
-			if filename := fset.Position(f.Pos()).Filename; strings.HasSuffix(filename, "_test.go") {
+			if filename := fset.Position(f.Pos()).Filename; p.isTest(filename, f.Name.Name) {
 				continue
 			}
 		}
@@ -171,8 +295,25 @@ func RunWithConfig(files []*ast.File, fset *token.FileSet, typeInfo *types.Info,
 
 	wg.Wait()
 
+	p.fileCount = len(filteredFiles)
 	p.ProcessResults()
 
+	return p.Issues(), nil
+}
+
+// Issues builds the list of issues from the results collected by ParseTree
+// or RunWithConfig, sorted by string and position.
+// Occurrences are counted per scope (see SetScope): test-file issues report
+// test-file counts and non-test issues report non-test counts.
+func (p *Parser) Issues() []Issue {
+	// Pre-allocate slice based on estimated result size
+	expectedIssues := len(p.strs) * 2
+	if expectedIssues > 1000 {
+		expectedIssues = 1000 // Cap at reasonable maximum
+	}
+
+	issueBuffer := make([]Issue, 0, expectedIssues)
+
 	// Process each string that passed the filters
 	p.stringMutex.RLock()
 	p.stringCountMutex.RLock()
@@ -183,7 +324,7 @@ func RunWithConfig(files []*ast.File, fset *token.FileSet, typeInfo *types.Info,
 	// Global count is a coarse prefilter; the reporting loop below
 	// re-applies minOccurrences per scope (test vs non-test).
 	for str := range p.strs {
-		if count := p.stringCount[str]; count >= p.minOccurrences {
+		if count := p.stringCount[str]; count >= p.minOccurrencesFor(p.strs[str]) || p.testConstantCandidate(str) {
 			stringKeys = append(stringKeys, str)
 		}
 	}
@@ -206,13 +347,12 @@ func RunWithConfig(files []*ast.File, fset *token.FileSet, typeInfo *types.Info,
 
 		sortPositions(positions)
 
-		var nonTestCount, testCount int
+		// Split the positions by scope, test and non-test code are
+		// always counted separately.
+		scopes := make(map[string][]ExtendedPos)
 		for _, pos := range positions {
-			if strings.HasSuffix(pos.Filename, testSuffix) {
-				testCount++
-			} else {
-				nonTestCount++
-			}
+			key := p.positionScope(pos)
+			scopes[key] = append(scopes[key], pos)
 		}
 
 		// Resolve matching constants per scope so that non-test issues
@@ -232,7 +372,7 @@ func RunWithConfig(files []*ast.File, fset *token.FileSet, typeInfo *types.Info,
 				sortConstants(csts)
 				anyMatchingConst = csts[0].Name
 				for _, cst := range csts {
-					if !strings.HasSuffix(cst.Filename, testSuffix) {
+					if !p.isTest(cst.Filename, cst.packageName) {
 						nonTestMatchingConst = cst.Name
 						break
 					}
@@ -240,34 +380,63 @@ func RunWithConfig(files []*ast.File, fset *token.FileSet, typeInfo *types.Info,
 			}
 		}
 
-		seen := make(map[string]bool)
+		// The literal is only classified once one of its scopes passes the
+		// thresholds, the classifier being costly
+		var category string
+		classified := false
+		reported := make(map[string]bool)
 		for _, pos := range positions {
-			if seen[pos.Filename] {
+			key := p.positionScope(pos)
+			if reported[key+"\x00"+pos.Filename] {
 				continue
 			}
-			seen[pos.Filename] = true
+			reported[key+"\x00"+pos.Filename] = true
 
-			isTest := strings.HasSuffix(pos.Filename, testSuffix)
+			isTest := p.isTest(pos.Filename, pos.packageName)
 
-			scopeCount := nonTestCount
-			if isTest {
-				scopeCount = testCount
+			// Test literals equal to a production constant are reported
+			// whatever their number
+			var testConst string
+			if isTest && p.testConstants {
+				testConst = p.productionConstant(str, pos)
 			}
 
-			if scopeCount < p.minOccurrences {
+			scopePositions := scopes[key]
+			if testConst == "" && (len(scopePositions) < p.minOccurrencesFor(scopePositions) || !p.spreadEnough(scopePositions)) {
 				continue
 			}
 
+			// Table cases are only worth a constant when they repeat
+			// across tables, ideally a production one
+			tableTest := p.tableTests == TableTestsSeparate && pos.InTableTest()
+			if tableTest && testConst == "" && tableCount(scopePositions) < 2 {
+				continue
+			}
+
+			if !classified {
+				category, classified = classify(p.categoryRules, str), true
+			}
+			if !p.categoryReported(category) {
+				break
+			}
+
 			matchingConst := nonTestMatchingConst
-			if isTest && matchingConst == "" {
+			if isTest && !tableTest && matchingConst == "" {
 				matchingConst = anyMatchingConst
 			}
+			if testConst != "" {
+				matchingConst = testConst
+			}
 
 			issueBuffer = append(issueBuffer, Issue{
 				Pos:              pos.Position,
-				OccurrencesCount: scopeCount,
+				OccurrencesCount: len(scopePositions),
 				Str:              str,
 				MatchingConst:    matchingConst,
+				Occurrences:      scopePositions,
+				Score:            issueScore(str, scopePositions, isTest),
+				Category:         category,
+				TableTest:        tableTest,
 			})
 		}
 	}
@@ -294,26 +463,44 @@ func RunWithConfig(files []*ast.File, fset *token.FileSet, typeInfo *types.Info,
 		// Report an issue for every duplicated const within the same scope.
 		// Test and non-test constants are compared independently so that a
 		// test constant is never flagged as duplicate of a production one.
+		// Constants have no enclosing function, the function scope groups
+		// them by file.
 		for _, str := range stringKeys {
-			allConsts := p.consts[str]
+			category := classify(p.categoryRules, str)
+			if !p.categoryReported(category) {
+				continue
+			}
+
+			allConsts := append([]ConstType(nil), p.consts[str]...)
+			sortConstants(allConsts)
 
-			var nonTestConsts, testConsts []ConstType
+			var keys []string
+			scopes := make(map[string][]ConstType)
 			for _, cst := range allConsts {
-				if strings.HasSuffix(cst.Filename, testSuffix) {
-					testConsts = append(testConsts, cst)
-				} else {
-					nonTestConsts = append(nonTestConsts, cst)
+				key := p.scopeKey(cst.Filename, cst.packageName, "")
+				if _, ok := scopes[key]; !ok {
+					keys = append(keys, key)
 				}
+				scopes[key] = append(scopes[key], cst)
 			}
+			// Non-test scopes first, each in order of their first constant
+			sort.SliceStable(keys, func(i, j int) bool {
+				first, second := scopes[keys[i]][0], scopes[keys[j]][0]
+				return !p.isTest(first.Filename, first.packageName) && p.isTest(second.Filename, second.packageName)
+			})
 
-			for _, scopeConsts := range [][]ConstType{nonTestConsts, testConsts} {
-				sortConstants(scopeConsts)
+			for _, key := range keys {
+				scopeConsts := scopes[key]
+				isTest := p.isTest(scopeConsts[0].Filename, scopeConsts[0].packageName)
 				for i := 1; i < len(scopeConsts); i++ {
+					pair := []ExtendedPos{{Position: scopeConsts[0].Position}, {Position: scopeConsts[i].Position}}
 					issueBuffer = append(issueBuffer, Issue{
 						Pos:            scopeConsts[i].Position,
 						Str:            str,
 						DuplicateConst: scopeConsts[0].Name,
 						DuplicatePos:   scopeConsts[0].Position,
+						Score:          issueScore(str, pair, isTest),
+						Category:       category,
 					})
 				}
 			}
@@ -322,8 +509,201 @@ func RunWithConfig(files []*ast.File, fset *token.FileSet, typeInfo *types.Info,
 		p.constMutex.RUnlock()
 	}
 
-	// Don't return the buffer to pool as the caller now owns it
-	return issueBuffer, nil
+	if p.sentinelErrors {
+		issueBuffer = append(issueBuffer, p.sentinelIssues()...)
+	}
+	if p.configKeys {
+		issueBuffer = append(issueBuffer, p.configKeyIssues()...)
+	}
+	if p.contextKeys {
+		issueBuffer = append(issueBuffer, p.contextKeyIssues()...)
+	}
+	if p.enums {
+		issueBuffer = append(issueBuffer, p.enumIssues()...)
+	}
+
+	return issueBuffer
+}
+
+// scopeKey identifies the scope a literal found in the given file and
+// function is counted in.
+func (p *Parser) scopeKey(filename, pkg, function string) string {
+	key := "prod"
+	if p.isTest(filename, pkg) {
+		key = "test"
+	}
+
+	switch p.scope {
+	case ScopePackage:
+		key += "\x00" + filepath.Dir(filename)
+	case ScopeFile:
+		key += "\x00" + filename
+	case ScopeFunction:
+		key += "\x00" + filename + "\x00" + function
+	}
+	return key
+}
+
+// testConstantCandidate reports whether a literal found in test code may
+// equal a production constant, in which case it is kept below the occurrence
+// thresholds. The caller must hold stringMutex.
+func (p *Parser) testConstantCandidate(str string) bool {
+	if !p.testConstants {
+		return false
+	}
+
+	p.constMutex.RLock()
+	defer p.constMutex.RUnlock()
+
+	for _, cst := range p.consts[str] {
+		if p.isTest(cst.Filename, cst.packageName) {
+			continue
+		}
+		for _, pos := range p.strs[str] {
+			if p.isTest(pos.Filename, pos.packageName) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// productionConstant returns the first production constant with the value
+// str that the test code at pos can use: an exported one, or one declared
+// in the same package. It returns "" when there is none.
+func (p *Parser) productionConstant(str string, pos ExtendedPos) string {
+	p.constMutex.RLock()
+	csts := append([]ConstType(nil), p.consts[str]...)
+	p.constMutex.RUnlock()
+
+	sortConstants(csts)
+	for _, cst := range csts {
+		if p.isTest(cst.Filename, cst.packageName) {
+			continue
+		}
+		samePackage := cst.packageName == pos.packageName && filepath.Dir(cst.Filename) == filepath.Dir(pos.Filename)
+		if token.IsExported(cst.Name) || samePackage {
+			return cst.Name
+		}
+	}
+	return ""
+}
+
+// scopedIssues reports the positions of a literal found by a detector. The
+// positions are split by scope like repeated strings, and the scopes with
+// at least min positions are reported once per file, or once per position
+// when perPosition is set. The issues are based on template.
+func (p *Parser) scopedIssues(str string, positions []ExtendedPos, min int, perPosition bool, template Issue) []Issue {
+	return p.categorized(p.scopeIssues(str, positions, min, perPosition, template))
+}
+
+// categorized labels the issues with the category of their literal and
+// drops those the category filters exclude.
+func (p *Parser) categorized(issues []Issue) []Issue {
+	categories := make(map[string]string)
+	kept := issues[:0]
+	for _, issue := range issues {
+		category, ok := categories[issue.Str]
+		if !ok {
+			category = classify(p.categoryRules, issue.Str)
+			categories[issue.Str] = category
+		}
+		if p.categoryReported(category) {
+			issue.Category = category
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// scopeIssues is scopedIssues without the categories.
+func (p *Parser) scopeIssues(str string, positions []ExtendedPos, min int, perPosition bool, template Issue) []Issue {
+	positions = append([]ExtendedPos(nil), positions...)
+	sortPositions(positions)
+
+	scopes := make(map[string][]ExtendedPos)
+	for _, pos := range positions {
+		key := p.scopeKey(pos.Filename, pos.packageName, pos.function)
+		scopes[key] = append(scopes[key], pos)
+	}
+
+	var issues []Issue
+	reported := make(map[string]bool)
+	for _, pos := range positions {
+		key := p.scopeKey(pos.Filename, pos.packageName, pos.function)
+		scopePositions := scopes[key]
+		if len(scopePositions) < min {
+			continue
+		}
+		if !perPosition {
+			if reported[key+"\x00"+pos.Filename] {
+				continue
+			}
+			reported[key+"\x00"+pos.Filename] = true
+		}
+
+		issue := template
+		issue.Pos = pos.Position
+		issue.Str = str
+		issue.OccurrencesCount = len(scopePositions)
+		issue.Occurrences = scopePositions
+		issue.Score = issueScore(str, scopePositions, p.isTest(pos.Filename, pos.packageName))
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// positionScope returns the scope key of a literal. With TableTestsSeparate,
+// the cases of table-driven tests are counted apart from the other test
+// literals.
+func (p *Parser) positionScope(pos ExtendedPos) string {
+	key := p.scopeKey(pos.Filename, pos.packageName, pos.function)
+	if p.tableTests == TableTestsSeparate && pos.InTableTest() {
+		key = "table\x00" + key
+	}
+	return key
+}
+
+// spreadEnough reports whether the positions span at least the minimum
+// number of files and packages.
+func (p *Parser) spreadEnough(positions []ExtendedPos) bool {
+	if p.minFiles <= 1 && p.minPackages <= 1 {
+		return true
+	}
+
+	files := make(map[string]bool)
+	packages := make(map[string]bool)
+	for _, pos := range positions {
+		files[pos.Filename] = true
+		packages[filepath.Dir(pos.Filename)] = true
+	}
+	return len(files) >= p.minFiles && len(packages) >= p.minPackages
+}
+
+// issueScore computes the priority of a literal found at the given positions:
+// the number of occurrences, weighted by the length of the literal and by how
+// widely it is spread across files and packages. Test code weighs half.
+func issueScore(str string, positions []ExtendedPos, test bool) float64 {
+	if len(positions) == 0 {
+		return 0
+	}
+
+	files := make(map[string]bool)
+	packages := make(map[string]bool)
+	for _, pos := range positions {
+		files[pos.Filename] = true
+		packages[filepath.Dir(pos.Filename)] = true
+	}
+
+	length := 1 + math.Log10(float64(max(1, utf8.RuneCountInString(str))))
+	spread := 1 + 0.5*float64(len(files)-1) + float64(len(packages)-1)
+	score := float64(len(positions)) * length * spread
+	if test {
+		score /= 2
+	}
+
+	return math.Round(score*100) / 100
 }
 
 // Run analyzes the provided AST files for duplicated strings or numbers
@@ -3,9 +3,13 @@ package goconst
 import (
 	"fmt"
 	"go/ast"
+	"go/importer"
 	"go/parser"
 	"go/token"
 	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 )
@@ -1489,6 +1493,134 @@ func example() {
 	}
 }
 
+func TestRunWithConfig_IgnoreFunctionsResolved(t *testing.T) {
+	code := `package example
+import (
+	"fmt"
+	l "log/slog"
+	"testing"
+)
+type Logger struct{}
+func (*Logger) Log(string) {}
+type server struct{ log *Logger }
+func example(t *testing.T, s *server) {
+	l.Info("slog")
+	l.Info("slog")
+	t.Errorf("testing")
+	t.Errorf("testing")
+	fmt.Sprintf("fmt")
+	fmt.Println("fmt")
+	s.log.Log("local")
+	s.log.Log("local")
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	info := resolvedTypes(fset, f)
+
+	tests := []struct {
+		name   string
+		ignore []string
+		want   []string
+	}{
+		{"written name", []string{"l.Info", "s.log.Log"}, []string{"fmt", "testing"}},
+		{"package name", []string{"slog.Info"}, []string{"fmt", "local", "testing"}},
+		{"import path", []string{"log/slog.Info"}, []string{"fmt", "local", "testing"}},
+		{"promoted method", []string{"(*testing.T).Errorf"}, []string{"fmt", "local", "slog"}},
+		{"method wildcard", []string{"(*example.com/example.Logger).*"}, []string{"fmt", "slog", "testing"}},
+		{"package wildcard", []string{"fmt.*"}, []string{"local", "slog", "testing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := RunWithConfig([]*ast.File{f}, fset, info, &Config{
+				MinStringLength: 3,
+				MinOccurrences:  2,
+				IgnoreFunctions: tt.ignore,
+			})
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.Str)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunWithConfig_IgnoreArguments(t *testing.T) {
+	code := `package example
+import "log/slog"
+func logf(format string, args ...any) {}
+func example() {
+	slog.Info("message", "key", "value")
+	slog.Info("message", "key", "value")
+	logf("format", "arg")
+	logf("format", "arg")
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	info := resolvedTypes(fset, f)
+
+	tests := []struct {
+		name   string
+		ignore []string
+		want   []string
+	}{
+		{"whole call", []string{"slog.Info"}, []string{"arg", "format"}},
+		{"index", []string{"slog.Info#0"}, []string{"arg", "format", "key", "value"}},
+		{"parameter", []string{"log/slog.Info#msg"}, []string{"arg", "format", "key", "value"}},
+		{"variadic tail", []string{"slog.Info#..."}, []string{"arg", "format", "message"}},
+		{"variadic parameter", []string{"logf#args"}, []string{"format", "key", "message", "value"}},
+		{"any function", []string{"#format", "#msg"}, []string{"arg", "key", "value"}},
+		{"out of range", []string{"logf#5"}, []string{"arg", "format", "key", "message", "value"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := RunWithConfig([]*ast.File{f}, fset, info, &Config{
+				MinStringLength: 3,
+				MinOccurrences:  2,
+				IgnoreFunctions: tt.ignore,
+			})
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.Str)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// resolvedTypes type-checks a file with its imports, as golangci-lint does.
+func resolvedTypes(fset *token.FileSet, f *ast.File) *types.Info {
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	cfg := &types.Config{Importer: importer.Default(), Error: func(err error) {}}
+	_ = types.NewChecker(cfg, fset, types.NewPackage("example.com/example", "example"), info).Files([]*ast.File{f})
+	return info
+}
+
 func TestRunWithConfig_IgnoreFunctions_Empty(t *testing.T) {
 	code := `package example
 func example() {
@@ -1530,3 +1662,398 @@ func checker(fset *token.FileSet) (*types.Checker, *types.Info) {
 	}
 	return types.NewChecker(cfg, fset, types.NewPackage("", "example"), info), info
 }
+
+func TestParserIssues(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"prod.go": `package example
+func prod() {
+	_ = "scoped"
+	_ = "scoped"
+}`,
+		"prod_test.go": `package example
+func helper() {
+	_ = "scoped"
+	_ = "scoped"
+	_ = "scoped"
+}`,
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(src), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	p := New(tempDir, "", "", false, false, false, false, false, 0, 0, 3, 2, map[Type]bool{})
+	if _, _, err := p.ParseTree(); err != nil {
+		t.Fatalf("ParseTree() error = %v", err)
+	}
+
+	issues := p.Issues()
+	if len(issues) != 2 {
+		t.Fatalf("Issues() returned %d issues, want 2", len(issues))
+	}
+
+	for _, issue := range issues {
+		want := 2
+		if strings.HasSuffix(issue.Pos.Filename, testSuffix) {
+			want = 3
+		}
+		if issue.OccurrencesCount != want || len(issue.Occurrences) != want {
+			t.Errorf("%s: OccurrencesCount = %d, len(Occurrences) = %d, want %d",
+				issue.Pos.Filename, issue.OccurrencesCount, len(issue.Occurrences), want)
+		}
+		if want := issueScore(issue.Str, issue.Occurrences, want == 3); issue.Score != want {
+			t.Errorf("%s: Score = %v, want %v", issue.Pos.Filename, issue.Score, want)
+		}
+		for _, occ := range issue.Occurrences {
+			if occ.Filename != issue.Pos.Filename {
+				t.Errorf("occurrence %s leaked into the %s scope", occ.Filename, issue.Pos.Filename)
+			}
+		}
+	}
+}
+
+func TestIssueScore(t *testing.T) {
+	pos := func(file string) ExtendedPos {
+		return ExtendedPos{Position: token.Position{Filename: file, Line: 1, Column: 1}}
+	}
+
+	tests := []struct {
+		name      string
+		str       string
+		positions []ExtendedPos
+		test      bool
+		want      float64
+	}{
+		{name: "no positions", str: "foo", want: 0},
+		{name: "single file", str: "foo", positions: []ExtendedPos{pos("a.go"), pos("a.go")}, want: 2.95},
+		{name: "two files", str: "foo", positions: []ExtendedPos{pos("a.go"), pos("b.go")}, want: 4.43},
+		{name: "two packages", str: "foo", positions: []ExtendedPos{pos("a/a.go"), pos("b/b.go")}, want: 7.39},
+		{name: "longer literal", str: "0123456789", positions: []ExtendedPos{pos("a.go"), pos("a.go")}, want: 4},
+		{name: "test code", str: "foo", positions: []ExtendedPos{pos("a_test.go"), pos("a_test.go")}, test: true, want: 1.48},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issueScore(tt.str, tt.positions, tt.test); got != tt.want {
+				t.Errorf("issueScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunWithConfig_ThresholdsByType(t *testing.T) {
+	code := `package example
+func example(s string) string {
+	switch s {
+	case "case-only":
+	}
+	switch s {
+	case "case-only":
+	}
+	println("call-twice")
+	println("call-twice")
+	println("call-many")
+	println("call-many")
+	println("call-many")
+	println("mixed")
+	if s == "mixed" {
+	}
+	switch s {
+	case "ab":
+	case "xy":
+	}
+	switch s {
+	case "ab":
+	}
+	return "xy"
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	chkr, info := checker(fset)
+	_ = chkr.Files([]*ast.File{f})
+
+	issues, err := Run([]*ast.File{f}, fset, info, &Config{
+		MinStringLength:      3,
+		MinOccurrences:       4,
+		MinOccurrencesByType: map[Type]int{Case: 2, Call: 3, Binary: 2},
+		MinLengthByType:      map[Type]int{Case: 2},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Str)
+	}
+	// "call-twice" is below the call threshold, "mixed" below the call
+	// threshold its binary occurrence does not lower, "xy" is only short
+	// enough in its case clause and appears once there.
+	want := []string{"ab", "call-many", "case-only"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+}
+
+func TestRunWithConfig_ConstLengthByType(t *testing.T) {
+	code := `package example
+const StateOk = "ok"
+func example(s string) {
+	switch s {
+	case "ok":
+	}
+	switch s {
+	case "ok":
+	}
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	chkr, info := checker(fset)
+	_ = chkr.Files([]*ast.File{f})
+
+	issues, err := Run([]*ast.File{f}, fset, info, &Config{
+		MinStringLength:    3,
+		MinOccurrences:     2,
+		MinLengthByType:    map[Type]int{Case: 2},
+		MatchWithConstants: true,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// The constant is shorter than the global minimum but long enough for
+	// the case clauses it matches.
+	if len(issues) != 1 || issues[0].MatchingConst != "StateOk" {
+		t.Errorf("issues = %+v, want one matching StateOk", issues)
+	}
+}
+
+func TestRunWithConfig_Scope(t *testing.T) {
+	sources := map[string]string{
+		"a/a.go": `package a
+func one() { _ = "shared"; _ = "shared" }
+func two() { _ = "shared"; _ = "in-file" }
+func (s *S) three() { _ = "in-file" }`,
+		"a/b.go": `package a
+func four() { _ = "shared" }`,
+		"b/c.go": `package b
+func five() { _ = "shared"; _ = "spread" }`,
+		"b/d.go": `package b
+func six() { _ = "spread" }`,
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range []string{"a/a.go", "a/b.go", "b/c.go", "b/d.go"} {
+		f, err := parser.ParseFile(fset, name, sources[name], 0)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", name, err)
+		}
+		files = append(files, f)
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{
+			name: "module",
+			cfg:  Config{Scope: ScopeModule},
+			want: []string{"in-file:2@a/a.go", "shared:5@a/a.go", "shared:5@a/b.go", "shared:5@b/c.go", "spread:2@b/c.go", "spread:2@b/d.go"},
+		},
+		{
+			name: "package",
+			cfg:  Config{Scope: ScopePackage},
+			want: []string{"in-file:2@a/a.go", "shared:4@a/a.go", "shared:4@a/b.go", "spread:2@b/c.go", "spread:2@b/d.go"},
+		},
+		{
+			name: "file",
+			cfg:  Config{Scope: ScopeFile},
+			want: []string{"in-file:2@a/a.go", "shared:3@a/a.go"},
+		},
+		{
+			name: "function",
+			cfg:  Config{Scope: ScopeFunction},
+			want: []string{"shared:2@a/a.go"},
+		},
+		{
+			name: "min files",
+			cfg:  Config{Scope: ScopePackage, MinFiles: 2},
+			want: []string{"shared:4@a/a.go", "shared:4@a/b.go", "spread:2@b/c.go", "spread:2@b/d.go"},
+		},
+		{
+			name: "min packages",
+			cfg:  Config{MinPackages: 2},
+			want: []string{"shared:5@a/a.go", "shared:5@a/b.go", "shared:5@b/c.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.MinStringLength = 3
+			cfg.MinOccurrences = 2
+
+			issues, err := RunWithConfig(files, fset, nil, &cfg)
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, fmt.Sprintf("%s:%d@%s", issue.Str, issue.OccurrencesCount, issue.Pos.Filename))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	for scope := ScopeModule; scope <= ScopeFunction; scope++ {
+		got, err := ParseScope(scope.String())
+		if err != nil || got != scope {
+			t.Errorf("ParseScope(%q) = %v, %v, want %v", scope.String(), got, err, scope)
+		}
+	}
+	if _, err := ParseScope("repository"); err == nil {
+		t.Error("ParseScope() should reject unknown names")
+	}
+	if got := Scope(9).String(); got != "Scope(9)" {
+		t.Errorf("Scope(9).String() = %q", got)
+	}
+}
+
+func TestRunWithConfig_IgnoreInFunctions(t *testing.T) {
+	code := `package example
+func init() { _ = "in-init"; _ = "in-init" }
+func TestThing() { _ = "in-test"; _ = "in-test" }
+func (s *Server) Start() { _ = "in-method"; _ = "in-method" }
+func (s Server) Stop() { _ = "in-method"; _ = "in-method" }
+func handler() {
+	f := func() { _ = "in-closure" }
+	f()
+	_ = "in-closure"
+}
+func keep() { _ = "kept"; _ = "kept" }`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{name: "none", want: []string{"in-closure", "in-init", "in-method", "in-test", "kept"}},
+		{name: "tests and init", patterns: []string{"Test*", "init"}, want: []string{"in-closure", "in-method", "kept"}},
+		{name: "receiver", patterns: []string{"Server.*"}, want: []string{"in-closure", "in-init", "in-test", "kept"}},
+		{name: "method names", patterns: []string{"*.Start", "*.Stop"}, want: []string{"in-closure", "in-init", "in-test", "kept"}},
+		{name: "function literals", patterns: []string{"hand*"}, want: []string{"in-init", "in-method", "in-test", "kept"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := RunWithConfig([]*ast.File{f}, fset, nil, &Config{
+				MinStringLength:   3,
+				MinOccurrences:    2,
+				IgnoreInFunctions: tt.patterns,
+			})
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				if len(got) == 0 || got[len(got)-1] != issue.Str {
+					got = append(got, issue.Str)
+				}
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := RunWithConfig([]*ast.File{f}, fset, nil, &Config{IgnoreInFunctions: []string{"Test["}}); err == nil {
+		t.Error("RunWithConfig() should reject malformed patterns")
+	}
+}
+
+func TestRunWithConfig_TestConstants(t *testing.T) {
+	sources := map[string]string{
+		"api/version.go": `package api
+const Version = "v3"
+const defaultRegion = "eu-west-1"
+const legacy = "v2"`,
+		"api/version_test.go": `package api
+func TestVersion(t *testing.T) {
+	_ = "v3"
+	_ = "eu-west-1"
+	_ = "v2"
+}`,
+		"client/client_test.go": `package client
+func TestClient(t *testing.T) {
+	_ = "v3"
+	_ = "eu-west-1"
+}`,
+		"api/other.go": `package api
+func f() { _ = "v3" }`,
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range []string{"api/version.go", "api/version_test.go", "client/client_test.go", "api/other.go"} {
+		f, err := parser.ParseFile(fset, name, sources[name], 0)
+		if err != nil {
+			t.Fatalf("Failed to parse: %v", err)
+		}
+		files = append(files, f)
+	}
+
+	run := func(testConstants bool) []string {
+		issues, err := RunWithConfig(files, fset, nil, &Config{
+			MinStringLength: 2,
+			MinOccurrences:  5,
+			TestConstants:   testConstants,
+		})
+		if err != nil {
+			t.Fatalf("RunWithConfig() error = %v", err)
+		}
+		var got []string
+		for _, issue := range issues {
+			got = append(got, fmt.Sprintf("%s %s=%s", issue.Pos.Filename, issue.Str, issue.MatchingConst))
+		}
+		sort.Strings(got)
+		return got
+	}
+
+	if got := run(false); len(got) != 0 {
+		t.Errorf("issues without TestConstants = %v, want none", got)
+	}
+
+	// Unexported constants are only usable from their own package,
+	// production code is not concerned.
+	want := []string{
+		"api/version_test.go eu-west-1=defaultRegion",
+		"api/version_test.go v2=legacy",
+		"api/version_test.go v3=Version",
+		"client/client_test.go v3=Version",
+	}
+	if got := run(true); strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package goconst
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Categories of the built-in literal classifier.
+const (
+	CategoryURL        = "url"
+	CategoryPath       = "path"
+	CategorySQL        = "sql"
+	CategoryHTTPHeader = "http-header"
+	CategoryMIMEType   = "mime-type"
+	CategoryRegexp     = "regexp"
+	CategoryEnvVar     = "env-var"
+	CategoryLogMessage = "log-message"
+	CategoryUUID       = "uuid"
+	CategoryProse      = "prose"
+	// CategoryOther is the category of literals no rule matched
+	CategoryOther = "other"
+)
+
+// categoryRule labels the literals matching a pattern.
+type categoryRule struct {
+	category string
+	pattern  *regexp.Regexp
+}
+
+// builtinCategories are tried in order, the first match wins. Rules for
+// literals with a recognizable syntax come before the looser ones.
+var builtinCategories = []categoryRule{
+	{CategoryUUID, regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)},
+	{CategoryURL, regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*://|mailto:)\S+$`)},
+	{CategoryMIMEType, regexp.MustCompile(`^(application|audio|font|image|message|model|multipart|text|video)/[\w.+-]+(\s*;.*)?$`)},
+	{CategorySQL, regexp.MustCompile(`(?is)^\s*(select\s.+\sfrom\s|insert\s+into\s|update\s.+\sset\s|delete\s+from\s|(create|alter|drop)\s+(table|index|view)\s|with\s.+\sas\s*\()`)},
+	{CategoryHTTPHeader, regexp.MustCompile(`^(Accept|Authorization|Connection|Cookie|Host|Location|Origin|Referer|Server|Upgrade|Vary|[A-Z][a-zA-Z0-9]*(-[A-Z][a-zA-Z0-9]*)+)$`)},
+	{CategoryEnvVar, regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)+$`)},
+	{CategoryPath, regexp.MustCompile(`^(\.{0,2}/|~/|[a-zA-Z]:\\)[^\s]*$|^[\w.-]+(/[\w.-]+)+$|^[\w-]+\.(go|json|ya?ml|toml|txt|csv|html?|sql|proto|pem|key|log|conf)$`)},
+	{CategoryRegexp, regexp.MustCompile(`^\^|[^\\]\$$|\\[dDwWsSbB]|\(\?[a-zA-Z]+\)|\[[^\]]+\][*+?]|\.[*+]`)},
+	{CategoryLogMessage, regexp.MustCompile(`%[-+# 0]*(\d+|\*)?(\.(\d+|\*))?[vTtbcdoOqxXUeEfFgGsp]`)},
+	{CategoryProse, regexp.MustCompile(`^\p{L}[\p{L}\p{N}'’,;:()-]*(\s+[\p{L}\p{N}'’,;:()-]+){2,}[.!?]?$`)},
+}
+
+// BuiltinCategories returns the categories of the built-in classifier.
+func BuiltinCategories() []string {
+	categories := make([]string, 0, len(builtinCategories)+1)
+	for _, rule := range builtinCategories {
+		categories = append(categories, rule.category)
+	}
+	return append(categories, CategoryOther)
+}
+
+// Classify returns the category of a literal according to the built-in
+// classifier, CategoryOther when no rule matches.
+func Classify(str string) string {
+	return classify(nil, str)
+}
+
+func classify(custom []categoryRule, str string) string {
+	for _, rules := range [][]categoryRule{custom, builtinCategories} {
+		for _, rule := range rules {
+			if rule.pattern.MatchString(str) {
+				if rule.category == CategoryRegexp {
+					// Only literals that are valid expressions
+					if _, err := regexp.Compile(str); err != nil {
+						continue
+					}
+				}
+				return rule.category
+			}
+		}
+	}
+	return CategoryOther
+}
+
+// SetCategoryPatterns extends the classifier with regular expressions per
+// category, which may be new categories or built-in ones. They are tried
+// before the built-in rules.
+func (p *Parser) SetCategoryPatterns(patterns map[string][]string) error {
+	var rules []categoryRule
+	for _, category := range sortedCategoryNames(patterns) {
+		for _, pattern := range patterns[category] {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid pattern for category %q: %w", category, err)
+			}
+			rules = append(rules, categoryRule{category: category, pattern: re})
+		}
+	}
+	p.categoryRules = rules
+	return nil
+}
+
+// SetCategoryFilters drops the issues whose category is in ignore, or, when
+// only is not empty, not in only.
+func (p *Parser) SetCategoryFilters(ignore, only []string) {
+	p.ignoreCategories = categorySet(ignore)
+	p.onlyCategories = categorySet(only)
+}
+
+// categoryReported reports whether issues of the given category pass the filters.
+func (p *Parser) categoryReported(category string) bool {
+	if p.ignoreCategories[category] {
+		return false
+	}
+	return len(p.onlyCategories) == 0 || p.onlyCategories[category]
+}
+
+func categorySet(categories []string) map[string]bool {
+	if len(categories) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		if category = strings.TrimSpace(category); category != "" {
+			set[category] = true
+		}
+	}
+	return set
+}
+
+func sortedCategoryNames(patterns map[string][]string) []string {
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,123 @@
+package goconst
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/api":                      CategoryURL,
+		"postgres://user@localhost/db":                 CategoryURL,
+		"mailto:ops@example.com":                       CategoryURL,
+		"/etc/goconst/config.yaml":                     CategoryPath,
+		"./testdata":                                   CategoryPath,
+		"internal/pkg/file.go":                         CategoryPath,
+		"config.json":                                  CategoryPath,
+		"SELECT id, name FROM users WHERE id = $1":     CategorySQL,
+		"insert into events (id) values (?)":           CategorySQL,
+		"Content-Type":                                 CategoryHTTPHeader,
+		"X-Request-Id":                                 CategoryHTTPHeader,
+		"Authorization":                                CategoryHTTPHeader,
+		"application/json":                             CategoryMIMEType,
+		"text/plain; charset=utf-8":                    CategoryMIMEType,
+		`^[a-z]+\d*$`:                                  CategoryRegexp,
+		`(?i)error`:                                    CategoryRegexp,
+		"DATABASE_URL":                                 CategoryEnvVar,
+		"failed to open %s: %v":                        CategoryLogMessage,
+		"123e4567-e89b-12d3-a456-426614174000":         CategoryUUID,
+		"The quick brown fox jumps over the lazy dog.": CategoryProse,
+		"please try again later":                       CategoryProse,
+		"foo":                                          CategoryOther,
+		"two words":                                    CategoryOther,
+		"[unbalanced+":                                 CategoryOther,
+		"camelCaseIdentifier":                          CategoryOther,
+	}
+
+	for str, want := range tests {
+		if got := Classify(str); got != want {
+			t.Errorf("Classify(%q) = %q, want %q", str, got, want)
+		}
+	}
+}
+
+func TestBuiltinCategories(t *testing.T) {
+	categories := BuiltinCategories()
+	if len(categories) != 11 || categories[len(categories)-1] != CategoryOther {
+		t.Errorf("BuiltinCategories() = %v", categories)
+	}
+}
+
+func TestRunWithConfig_Categories(t *testing.T) {
+	code := `package example
+func example() {
+	println("https://example.com")
+	println("https://example.com")
+	println("DATABASE_URL")
+	println("DATABASE_URL")
+	println("TICKET-123")
+	println("TICKET-123")
+	println("plain")
+	println("plain")
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{
+			name: "labels",
+			want: []string{"DATABASE_URL=env-var", "TICKET-123=other", "https://example.com=url", "plain=other"},
+		},
+		{
+			name: "custom patterns first",
+			cfg:  Config{CategoryPatterns: map[string][]string{"ticket": {`^[A-Z]+-\d+$`}}},
+			want: []string{"DATABASE_URL=env-var", "TICKET-123=ticket", "https://example.com=url", "plain=other"},
+		},
+		{
+			name: "ignore categories",
+			cfg:  Config{IgnoreCategories: []string{"url", "env-var"}},
+			want: []string{"TICKET-123=other", "plain=other"},
+		},
+		{
+			name: "only categories",
+			cfg:  Config{OnlyCategories: []string{"other"}},
+			want: []string{"TICKET-123=other", "plain=other"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.MinStringLength = 3
+			cfg.MinOccurrences = 2
+
+			issues, err := RunWithConfig([]*ast.File{f}, fset, nil, &cfg)
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.Str+"="+issue.Category)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	_, err = RunWithConfig([]*ast.File{f}, fset, nil, &Config{CategoryPatterns: map[string][]string{"bad": {"("}}})
+	if err == nil {
+		t.Error("RunWithConfig() should reject invalid category patterns")
+	}
+}
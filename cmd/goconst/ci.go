@@ -0,0 +1,96 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jgautheron/goconst"
+)
+
+// printGitHubActions writes one workflow command per issue so that GitHub
+// Actions shows the findings inline on pull requests.
+func printGitHubActions(out io.Writer, issues []goconst.Issue) error {
+	for _, issue := range issues {
+		_, err := fmt.Fprintf(out, "::warning file=%s,line=%d,col=%d,title=%s::%s\n",
+			escapeGitHubProperty(filepath.ToSlash(issue.Pos.Filename)),
+			issue.Pos.Line,
+			issue.Pos.Column,
+			escapeGitHubProperty("goconst ("+issueRule(issue)+")"),
+			escapeGitHubData(issueMessage(issue)),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeGitHubData escapes the message part of a workflow command.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(escapeGitHubData(s))
+}
+
+type codeClimateIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeClimateLocation `json:"location"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+}
+
+// printGitLab writes the issues as a GitLab Code Quality (Code Climate) report.
+func printGitLab(out io.Writer, issues []goconst.Issue) error {
+	report := make([]codeClimateIssue, 0, len(issues))
+	seen := map[string]int{}
+
+	for _, issue := range issues {
+		path := filepath.ToSlash(issue.Pos.Filename)
+		rule := issueRule(issue)
+
+		// Line numbers are left out of the fingerprint so that an issue
+		// keeps its identity when unrelated code above it moves. Issues
+		// sharing rule, file and value are told apart by their rank.
+		key := rule + "\x00" + path + "\x00" + issue.Str
+		rank := seen[key]
+		seen[key]++
+
+		report = append(report, codeClimateIssue{
+			Description: issueMessage(issue),
+			CheckName:   "goconst." + rule,
+			Fingerprint: fingerprint(key, rank),
+			Severity:    "minor",
+			Location: codeClimateLocation{
+				Path:  path,
+				Lines: codeClimateLines{Begin: issue.Pos.Line},
+			},
+		})
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func fingerprint(key string, rank int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, rank)))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPrintGitHubActions(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printGitHubActions(&buf, testIssues()); err != nil {
+		t.Fatalf("printGitHubActions() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), buf.String())
+	}
+
+	want := `::warning file=a.go,line=3,col=2,title=goconst (repeated-string)::3 occurrence(s) of "foo" found`
+	if lines[0] != want {
+		t.Errorf("first line = %q, want %q", lines[0], want)
+	}
+	if !strings.Contains(lines[3], "declared at c.go:2:7") {
+		t.Errorf("duplicate constant line = %q", lines[3])
+	}
+}
+
+func TestEscapeGitHub(t *testing.T) {
+	if got := escapeGitHubData("50%\nnext"); got != "50%25%0Anext" {
+		t.Errorf("escapeGitHubData() = %q", got)
+	}
+	if got := escapeGitHubProperty("C:\\a,b.go"); got != "C%3A\\a%2Cb.go" {
+		t.Errorf("escapeGitHubProperty() = %q", got)
+	}
+}
+
+func TestPrintGitLab(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printGitLab(&buf, testIssues()); err != nil {
+		t.Fatalf("printGitLab() error = %v", err)
+	}
+
+	var report []codeClimateIssue
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid Code Quality JSON: %v\n%s", err, buf.String())
+	}
+	if len(report) != 4 {
+		t.Fatalf("got %d issues, want 4", len(report))
+	}
+
+	fingerprints := map[string]bool{}
+	for _, issue := range report {
+		if fingerprints[issue.Fingerprint] {
+			t.Errorf("duplicate fingerprint %s", issue.Fingerprint)
+		}
+		fingerprints[issue.Fingerprint] = true
+	}
+
+	// Moving an issue to another line must not change its fingerprint.
+	moved := testIssues()
+	moved[0].Pos.Line += 10
+	buf.Reset()
+	if err := printGitLab(&buf, moved); err != nil {
+		t.Fatalf("printGitLab() error = %v", err)
+	}
+	var movedReport []codeClimateIssue
+	if err := json.Unmarshal(buf.Bytes(), &movedReport); err != nil {
+		t.Fatalf("invalid Code Quality JSON: %v", err)
+	}
+	if movedReport[0].Fingerprint != report[0].Fingerprint {
+		t.Error("fingerprint changed when the issue moved to another line")
+	}
+	if movedReport[0].Location.Lines.Begin != 13 {
+		t.Errorf("Location.Lines.Begin = %d, want 13", movedReport[0].Location.Lines.Begin)
+	}
+}
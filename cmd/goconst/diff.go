@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jgautheron/goconst"
+)
+
+// changedLines maps cleaned absolute file paths to the set of lines
+// that were added or modified in them.
+type changedLines map[string]map[int]bool
+
+// loadChangedLines builds the set of changed lines requested on the command line.
+// It returns nil when no diff-aware flag is set, meaning every line counts as changed.
+func loadChangedLines() (changedLines, error) {
+	set := 0
+	for _, ok := range []bool{*flagNewFromPatch != "", *flagNewFromRev != "", *flagNewStaged} {
+		if ok {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, fmt.Errorf("-new-from-patch, -new-from-rev and -new-staged are mutually exclusive")
+	}
+
+	switch {
+	case *flagNewFromPatch != "":
+		f, err := os.Open(*flagNewFromPatch)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		// Patch paths are taken relative to the working directory,
+		// which is where patches are usually generated and applied.
+		root, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		return parseUnifiedDiff(f, root)
+	case *flagNewFromRev != "":
+		return gitChangedLines(*flagNewFromRev)
+	case *flagNewStaged:
+		return gitChangedLines("--cached")
+	}
+	return nil, nil
+}
+
+// gitChangedLines runs git diff against the given revision (or "--cached"
+// for the staging area) and parses its output. Against a revision, every
+// line of the untracked files counts as changed, as git diff leaves them out.
+func gitChangedLines(rev string) (changedLines, error) {
+	top, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	root := strings.TrimSpace(string(top))
+
+	out, err := gitOutput("diff", "--no-color", "--no-ext-diff", "--no-renames", "-U0", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	changes, err := parseUnifiedDiff(bytes.NewReader(out), root)
+	if err != nil || rev == "--cached" {
+		return changes, err
+	}
+
+	untracked, err := gitOutput("-C", root, "ls-files", "--others", "--exclude-standard", "-z", "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(string(untracked), "\x00") {
+		if name == "" {
+			continue
+		}
+		path := filepath.Clean(filepath.Join(root, name))
+		if changes[path], err = allLines(path); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// allLines returns every line of a file.
+func allLines(path string) (map[int]bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines := map[int]bool{}
+	for i := 0; i <= bytes.Count(content, []byte("\n")); i++ {
+		lines[i+1] = true
+	}
+	return lines, nil
+}
+
+// gitOutput runs the local git binary and returns its standard output.
+func gitOutput(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// parseUnifiedDiff extracts the added lines of every file in a unified diff.
+// File names are resolved against root and the conventional a/ and b/
+// prefixes are stripped.
+func parseUnifiedDiff(r io.Reader, root string) (changedLines, error) {
+	changes := changedLines{}
+
+	var (
+		current map[int]bool
+		line    int
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			name := diffFileName(strings.TrimPrefix(text, "+++ "))
+			if name == "" {
+				current = nil
+				continue
+			}
+			path := name
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(root, path)
+			}
+			path = filepath.Clean(path)
+			if changes[path] == nil {
+				changes[path] = map[int]bool{}
+			}
+			current = changes[path]
+		case strings.HasPrefix(text, "--- "):
+			// The old file name is irrelevant, only new lines are reported.
+		case strings.HasPrefix(text, "@@ "):
+			start, err := hunkStart(text)
+			if err != nil {
+				return nil, err
+			}
+			line = start
+		case current == nil:
+			continue
+		case strings.HasPrefix(text, "+"):
+			current[line] = true
+			line++
+		case strings.HasPrefix(text, " "):
+			line++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// diffFileName returns the path of a "+++" header, or an empty string
+// when the file was deleted.
+func diffFileName(header string) string {
+	// Non-git diffs may append a tab-separated timestamp.
+	if i := strings.IndexByte(header, '\t'); i >= 0 {
+		header = header[:i]
+	}
+	if strings.HasPrefix(header, `"`) {
+		if unquoted, err := strconv.Unquote(header); err == nil {
+			header = unquoted
+		}
+	}
+	if header == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(header, "a/") || strings.HasPrefix(header, "b/") {
+		header = header[2:]
+	}
+	return header
+}
+
+// hunkStart parses the first line number of the new file in a hunk
+// header such as "@@ -12,3 +14,5 @@".
+func hunkStart(header string) (int, error) {
+	fields := strings.Fields(header)
+	for _, field := range fields[1:] {
+		if !strings.HasPrefix(field, "+") {
+			continue
+		}
+		start, _, _ := strings.Cut(field[1:], ",")
+		n, err := strconv.Atoi(start)
+		if err != nil {
+			return 0, fmt.Errorf("invalid hunk header %q", header)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("invalid hunk header %q", header)
+}
+
+// contains reports whether the given position lies on a changed line.
+func (c changedLines) contains(pos token.Position) bool {
+	path, err := filepath.Abs(pos.Filename)
+	if err != nil {
+		return false
+	}
+	if lines, ok := c[path]; ok {
+		return lines[pos.Line]
+	}
+	// git reports paths below the resolved repository root, which may
+	// differ from the working directory when it contains symlinks.
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return c[resolved][pos.Line]
+	}
+	return false
+}
+
+// filter keeps the issues of strings with at least one occurrence on a
+// changed line, and the duplicate constants declared on a changed line or
+// duplicating one. Occurrence counts are left untouched since they span the
+// whole tree.
+func (c changedLines) filter(issues []goconst.Issue) []goconst.Issue {
+	filtered := make([]goconst.Issue, 0, len(issues))
+	for _, issue := range issues {
+		changed := false
+		if issue.DuplicateConst != "" {
+			changed = c.contains(issue.Pos) || c.contains(issue.DuplicatePos)
+		} else {
+			for _, occ := range issue.Occurrences {
+				if c.contains(occ.Position) {
+					changed = true
+					break
+				}
+			}
+		}
+		if changed {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jgautheron/goconst"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	diff := `diff --git a/pkg/a.go b/pkg/a.go
+index 1111111..2222222 100644
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -3,0 +4,2 @@ func a() {
++	x := "new"
++	y := "new"
+@@ -10,3 +12,3 @@ func b() {
+ 	keep := 1
+-	old := "gone"
++	changed := "here"
+ 	keep2 := 2
+diff --git a/pkg/removed.go b/pkg/removed.go
+--- a/pkg/removed.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package pkg
+--- b.go	2024-01-01 00:00:00
++++ b.go	2024-01-02 00:00:00
+@@ -1 +1 @@
+-package b
++package c
+\ No newline at end of file
+`
+	changes, err := parseUnifiedDiff(strings.NewReader(diff), "/repo")
+	if err != nil {
+		t.Fatalf("parseUnifiedDiff() error = %v", err)
+	}
+
+	want := map[string][]int{
+		filepath.Clean("/repo/pkg/a.go"): {4, 5, 13},
+		filepath.Clean("/repo/b.go"):     {1},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("parseUnifiedDiff() returned %d files, want %d: %v", len(changes), len(want), changes)
+	}
+	for file, lines := range want {
+		if len(changes[file]) != len(lines) {
+			t.Errorf("%s: got lines %v, want %v", file, changes[file], lines)
+		}
+		for _, line := range lines {
+			if !changes[file][line] {
+				t.Errorf("%s: line %d should be marked as changed", file, line)
+			}
+		}
+	}
+}
+
+func TestHunkStart(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    int
+		wantErr bool
+	}{
+		{header: "@@ -1,2 +3,4 @@", want: 3},
+		{header: "@@ -1 +7 @@ func main() {", want: 7},
+		{header: "@@ -1,2 @@", wantErr: true},
+		{header: "@@ -1 +x @@", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := hunkStart(tt.header)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("hunkStart(%q) error = %v, wantErr %v", tt.header, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("hunkStart(%q) = %d, want %d", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestChangedLinesFilter(t *testing.T) {
+	pos := func(file string, line int) token.Position {
+		return token.Position{Filename: file, Line: line, Column: 1}
+	}
+	abs, err := filepath.Abs("a.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := changedLines{abs: {10: true}}
+	touched := []goconst.ExtendedPos{{Position: pos("a.go", 1)}, {Position: pos("b.go", 10)}, {Position: pos("a.go", 10)}}
+	untouched := []goconst.ExtendedPos{{Position: pos("a.go", 1)}, {Position: pos("a.go", 2)}}
+
+	issues := []goconst.Issue{
+		{Pos: pos("a.go", 1), Str: "touched", OccurrencesCount: 3, Occurrences: touched},
+		{Pos: pos("b.go", 10), Str: "touched", OccurrencesCount: 3, Occurrences: touched},
+		{Pos: pos("a.go", 1), Str: "untouched", OccurrencesCount: 2, Occurrences: untouched},
+		{Pos: pos("a.go", 10), Str: "dup", DuplicateConst: "DupA", DuplicatePos: pos("a.go", 3)},
+		{Pos: pos("a.go", 30), Str: "dup", DuplicateConst: "DupA", DuplicatePos: pos("a.go", 10)},
+		{Pos: pos("a.go", 31), Str: "other", DuplicateConst: "OtherA", DuplicatePos: pos("a.go", 3)},
+	}
+
+	filtered := changes.filter(issues)
+
+	var got []string
+	for _, issue := range filtered {
+		got = append(got, issue.Str+"@"+issue.Pos.String())
+	}
+	want := []string{"touched@a.go:1:1", "touched@b.go:10:1", "dup@a.go:10:1", "dup@a.go:30:1"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("filter() = %v, want %v", got, want)
+	}
+	if filtered[1].OccurrencesCount != 3 {
+		t.Errorf("occurrence count should be left untouched, got %d", filtered[1].OccurrencesCount)
+	}
+}
+
+func TestRunNewFromPatch(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "patched.go")
+	testContent := `package test
+func test() {
+	a := "existing"
+	b := "existing"
+	c := "introduced"
+	d := "introduced"
+}`
+	if err := os.WriteFile(testFile, []byte(testContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	patchFile := filepath.Join(tempDir, "changes.diff")
+	patch := "--- a/" + testFile + "\n+++ b/" + testFile + "\n@@ -4,0 +6 @@\n+\td := \"introduced\"\n"
+	if err := os.WriteFile(patchFile, []byte(patch), 0644); err != nil {
+		t.Fatalf("Failed to write patch file: %v", err)
+	}
+
+	oldStdout := os.Stdout
+	oldPatch := *flagNewFromPatch
+	*flagNewFromPatch = patchFile
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	defer func() {
+		os.Stdout = oldStdout
+		*flagNewFromPatch = oldPatch
+	}()
+
+	hasIssues, err := run(tempDir)
+	if err := w.Close(); err != nil {
+		t.Errorf("Failed to close writer: %v", err)
+	}
+	out, _ := io.ReadAll(r)
+	output := string(out)
+
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !hasIssues {
+		t.Error("run() returned false, expected true")
+	}
+	if !strings.Contains(output, "introduced") {
+		t.Errorf("expected output to report the introduced string, got:\n%s", output)
+	}
+	if strings.Contains(output, "existing") {
+		t.Errorf("expected output to omit the pre-existing string, got:\n%s", output)
+	}
+}
+
+func TestGitChangedLinesUntracked(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	git("init", "-q")
+	write("a.go", "package a\n")
+	git("add", "a.go")
+	git("commit", "-q", "-m", "init")
+	write("a.go", "package a\n\nvar x = 1\n")
+	write("c.go", "package a\n\nvar y = 2")
+	write("notes.txt", "untracked, but not Go\n")
+
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(oldDir)
+	}()
+
+	changes, err := gitChangedLines("HEAD")
+	if err != nil {
+		t.Fatalf("gitChangedLines() error = %v", err)
+	}
+	if !changes[filepath.Join(dir, "a.go")][3] || changes[filepath.Join(dir, "a.go")][1] {
+		t.Errorf("a.go changes = %v, want line 3 only", changes[filepath.Join(dir, "a.go")])
+	}
+	if c := changes[filepath.Join(dir, "c.go")]; !c[1] || !c[3] {
+		t.Errorf("every line of the untracked c.go should count as changed, got %v", c)
+	}
+	if _, ok := changes[filepath.Join(dir, "notes.txt")]; ok {
+		t.Error("untracked files other than Go files should be left out")
+	}
+
+	staged, err := gitChangedLines("--cached")
+	if err != nil {
+		t.Fatalf("gitChangedLines(--cached) error = %v", err)
+	}
+	if _, ok := staged[filepath.Join(dir, "c.go")]; ok {
+		t.Error("untracked files are not staged")
+	}
+}
+
+func TestLoadChangedLinesExclusive(t *testing.T) {
+	oldRev, oldStaged := *flagNewFromRev, *flagNewStaged
+	defer func() {
+		*flagNewFromRev, *flagNewStaged = oldRev, oldStaged
+	}()
+
+	*flagNewFromRev = "main"
+	*flagNewStaged = true
+	if _, err := loadChangedLines(); err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("loadChangedLines() error = %v, want a mutually exclusive error", err)
+	}
+}
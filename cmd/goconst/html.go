@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"go/token"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jgautheron/goconst"
+)
+
+// snippetContext is the number of source lines shown around each occurrence.
+const snippetContext = 2
+
+type htmlReport struct {
+	Total   int
+	Entries []htmlEntry
+}
+
+// htmlEntry describes a duplicated literal, or a duplicated constant value,
+// within a single scope.
+type htmlEntry struct {
+	issueGroup
+	Files    []string
+	Snippets []htmlSnippet
+}
+
+type htmlSnippet struct {
+	Location string
+	Label    string
+	Lines    []htmlLine
+}
+
+type htmlLine struct {
+	Number  int
+	Current bool
+	Before  string
+	Mark    string
+	After   string
+}
+
+// printHTML writes a self-contained HTML page listing every duplicated literal
+// with source snippets around each occurrence. It needs no external assets.
+func printHTML(out io.Writer, issues []goconst.Issue) error {
+	sources := sourceCache{}
+	report := htmlReport{}
+
+	for _, group := range groupIssues(issues) {
+		entry := htmlEntry{issueGroup: group, Files: group.files()}
+		for _, loc := range group.Locations {
+			entry.Snippets = append(entry.Snippets, sources.snippet(loc.Position, loc.Label))
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+	report.Total = len(report.Entries)
+
+	return htmlTemplate.Execute(out, report)
+}
+
+// sourceCache holds the lines of the source files referenced by the report.
+type sourceCache map[string][]string
+
+func (c sourceCache) lines(filename string) []string {
+	if lines, ok := c[filename]; ok {
+		return lines
+	}
+
+	var lines []string
+	if f, err := os.Open(filename); err == nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		_ = f.Close()
+	}
+	c[filename] = lines
+	return lines
+}
+
+// snippet extracts the lines around pos, highlighting the literal found at it.
+func (c sourceCache) snippet(pos token.Position, label string) htmlSnippet {
+	s := htmlSnippet{Location: pos.String(), Label: label}
+
+	lines := c.lines(pos.Filename)
+	first := pos.Line - snippetContext
+	if first < 1 {
+		first = 1
+	}
+	last := pos.Line + snippetContext
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	for n := first; n <= last; n++ {
+		text := lines[n-1]
+		line := htmlLine{Number: n, Before: text}
+		if n == pos.Line {
+			line.Current = true
+			start := pos.Column - 1
+			if start >= 0 && start < len(text) {
+				end := start + literalLength(text[start:])
+				line.Before, line.Mark, line.After = text[:start], text[start:end], text[end:]
+			}
+		}
+		s.Lines = append(s.Lines, line)
+	}
+	return s
+}
+
+// literalLength returns the length of the literal token at the start of s.
+func literalLength(s string) int {
+	switch s[0] {
+	case '"':
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+		return len(s)
+	case '`':
+		if i := strings.IndexByte(s[1:], '`'); i >= 0 {
+			return i + 2
+		}
+		return len(s)
+	default:
+		i := strings.IndexFunc(s, func(r rune) bool {
+			return !strings.ContainsRune("0123456789abcdefABCDEFxXoObB_.+-pPi", r)
+		})
+		if i < 0 {
+			return len(s)
+		}
+		return i
+	}
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>goconst report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.5em; }
+input { padding: .4em; width: 24em; margin-bottom: 1em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .4em .6em; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+th { cursor: pointer; background: #f6f8fa; user-select: none; }
+th.asc::after { content: " \25B2"; }
+th.desc::after { content: " \25BC"; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: .9em; }
+pre { background: #f6f8fa; padding: .5em; margin: .3em 0 1em; overflow-x: auto; }
+.location { font-size: .85em; color: #57606a; }
+.num { color: #8c959f; display: inline-block; width: 4em; text-align: right; margin-right: 1em; }
+.current { background: #fff8c5; }
+mark { background: #ffd33d; }
+.rule { font-size: .8em; padding: .1em .4em; border-radius: 1em; background: #ddf4ff; }
+</style>
+</head>
+<body>
+<h1>goconst report</h1>
+<p>{{.Total}} duplicated value(s) found.</p>
+<input id="filter" type="search" placeholder="Filter by literal, file or constant">
+<table id="report">
+<thead>
+<tr><th data-key="str">Literal</th><th data-key="count" data-numeric="1">Occurrences</th><th data-key="files" data-numeric="1">Files</th><th data-key="rule">Rule</th><th data-key="category">Category</th><th>Constants</th></tr>
+</thead>
+{{range .Entries}}<tbody data-str="{{.Str}}" data-count="{{.Count}}" data-files="{{len .Files}}" data-rule="{{.Rule}}" data-category="{{.Category}}" data-search="{{.Str}} {{.Category}} {{range .Files}}{{.}} {{end}}{{range .Constants}}{{.}} {{end}}">
+<tr>
+<td><code>{{printf "%q" .Str}}</code></td>
+<td>{{.Count}}</td>
+<td>{{len .Files}}</td>
+<td><span class="rule">{{.Rule}}</span></td>
+<td>{{.Category}}</td>
+<td>{{range .Constants}}<code>{{.}}</code> {{end}}</td>
+</tr>
+<tr><td colspan="6"><details><summary>{{range $i, $f := .Files}}{{if $i}}, {{end}}{{$f}}{{end}}</summary>
+{{range .Snippets}}<div class="location">{{.Location}}{{if .Label}} ({{.Label}}){{end}}</div>
+<pre>{{range .Lines}}<span{{if .Current}} class="current"{{end}}><span class="num">{{.Number}}</span>{{.Before}}{{if .Mark}}<mark>{{.Mark}}</mark>{{end}}{{.After}}</span>
+{{end}}</pre>
+{{end}}</details></td></tr>
+</tbody>
+{{end}}</table>
+<script>
+(function () {
+  var table = document.getElementById("report");
+  var filter = document.getElementById("filter");
+  filter.addEventListener("input", function () {
+    var q = filter.value.toLowerCase();
+    table.querySelectorAll("tbody").forEach(function (tb) {
+      tb.style.display = tb.dataset.search.toLowerCase().indexOf(q) === -1 ? "none" : "";
+    });
+  });
+  table.querySelectorAll("th[data-key]").forEach(function (th) {
+    th.addEventListener("click", function () {
+      var key = th.dataset.key, numeric = th.dataset.numeric;
+      var asc = !th.classList.contains("asc");
+      table.querySelectorAll("th").forEach(function (h) { h.classList.remove("asc", "desc"); });
+      th.classList.add(asc ? "asc" : "desc");
+      var bodies = Array.prototype.slice.call(table.querySelectorAll("tbody"));
+      bodies.sort(function (a, b) {
+        var x = a.dataset[key], y = b.dataset[key];
+        var c = numeric ? x - y : x.localeCompare(y);
+        return asc ? c : -c;
+      });
+      bodies.forEach(function (tb) { table.appendChild(tb); });
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
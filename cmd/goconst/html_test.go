@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jgautheron/goconst"
+)
+
+func TestPrintHTML(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.go")
+	src := `package a
+
+func a() {
+	_ = "dup<>"
+	_ = "dup<>"
+}
+`
+	if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	occurrences := []goconst.ExtendedPos{
+		{Position: token.Position{Filename: file, Line: 4, Column: 6}, Context: goconst.Assignment},
+		{Position: token.Position{Filename: file, Line: 5, Column: 6}, Context: goconst.Assignment},
+	}
+	issues := []goconst.Issue{
+		{Pos: occurrences[0].Position, Str: "dup<>", OccurrencesCount: 2, Occurrences: occurrences, MatchingConst: "Dup"},
+		{
+			Pos:            token.Position{Filename: file, Line: 5, Column: 6},
+			Str:            "other",
+			DuplicateConst: "Other",
+			DuplicatePos:   token.Position{Filename: file, Line: 4, Column: 6},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := printHTML(&buf, issues); err != nil {
+		t.Fatalf("printHTML() error = %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"<!DOCTYPE html>",
+		"2 duplicated value(s) found.",
+		`<code>&#34;dup&lt;&gt;&#34;</code>`,
+		`<mark>&#34;dup&lt;&gt;&#34;</mark>`,
+		"<code>Dup</code>",
+		"<code>Other</code>",
+		"(assignment)",
+		"(duplicate constant)",
+		"<script>",
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML output missing %q", want)
+		}
+	}
+	if strings.Contains(out, "dup<>") {
+		t.Error("literal should be HTML escaped")
+	}
+	for _, external := range []string{"<link", "src=", "http://", "https://"} {
+		if strings.Contains(out, external) {
+			t.Errorf("HTML output should not reference external assets, found %q", external)
+		}
+	}
+}
+
+func TestLiteralLength(t *testing.T) {
+	tests := map[string]int{
+		`"abc", x`:      5,
+		`"a\"b" + c`:    6,
+		"`raw` + x":     5,
+		"12345)":        5,
+		"0x1F, 2":       4,
+		`"unterminated`: 13,
+	}
+	for in, want := range tests {
+		if got := literalLength(in); got != want {
+			t.Errorf("literalLength(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
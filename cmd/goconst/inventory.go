@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jgautheron/goconst"
+)
+
+// configInventory lists the configuration keys, as printed by -config-inventory.
+type configInventory struct {
+	Version int              `json:"version"`
+	Run     jsonRun          `json:"run"`
+	Keys    []inventoryEntry `json:"keys"`
+}
+
+// inventoryEntry is a configuration key with the places reading and defining it.
+type inventoryEntry struct {
+	Source      string         `json:"source"`
+	Key         string         `json:"key"`
+	Reads       []jsonPosition `json:"reads"`
+	Definitions []jsonPosition `json:"definitions"`
+	Documented  bool           `json:"documented"`
+}
+
+func newConfigInventory(meta jsonRun, keys []goconst.ConfigKey) configInventory {
+	inventory := configInventory{
+		Version: jsonReportVersion,
+		Run:     meta,
+		Keys:    make([]inventoryEntry, 0, len(keys)),
+	}
+	for _, key := range keys {
+		entry := inventoryEntry{
+			Source:      key.Source,
+			Key:         key.Key,
+			Reads:       make([]jsonPosition, 0, len(key.Reads)),
+			Definitions: make([]jsonPosition, 0, len(key.Definitions)),
+			Documented:  key.Documented,
+		}
+		for _, pos := range key.Reads {
+			entry.Reads = append(entry.Reads, newJSONPosition(pos))
+		}
+		for _, pos := range key.Definitions {
+			entry.Definitions = append(entry.Definitions, newJSONPosition(pos))
+		}
+		inventory.Keys = append(inventory.Keys, entry)
+	}
+	return inventory
+}
+
+// printInventory writes the inventory of the configuration keys, with one
+// line per key followed by the places defining and reading it in text.
+func printInventory(out io.Writer, inventory configInventory, output string) error {
+	switch output {
+	case "json":
+		return json.NewEncoder(out).Encode(inventory)
+	case "text":
+	default:
+		return fmt.Errorf("unsupported output format for -config-inventory: %s", output)
+	}
+
+	for _, key := range inventory.Keys {
+		documented := ""
+		if key.Documented {
+			documented = ", documented"
+		}
+		fmt.Fprintf(out, "%s %q: %d read(s), %d definition(s)%s\n",
+			key.Source, key.Key, len(key.Reads), len(key.Definitions), documented)
+		for _, pos := range key.Definitions {
+			fmt.Fprintf(out, "\tdefined at %s:%d:%d\n", pos.Filename, pos.Line, pos.Column)
+		}
+		for _, pos := range key.Reads {
+			fmt.Fprintf(out, "\tread at %s:%d:%d\n", pos.Filename, pos.Line, pos.Column)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/jgautheron/goconst"
+)
+
+func testConfigKeys() []goconst.ConfigKey {
+	return []goconst.ConfigKey{
+		{
+			Source: goconst.ConfigSourceEnv,
+			Key:    "DATABASE_URL",
+			Reads: []token.Position{
+				{Filename: "db.go", Line: 3, Column: 20},
+				{Filename: "migrate.go", Line: 8, Column: 14},
+			},
+			Documented: true,
+		},
+		{
+			Source:      goconst.ConfigSourceFlag,
+			Key:         "verbose",
+			Definitions: []token.Position{{Filename: "main.go", Line: 10, Column: 25}},
+		},
+	}
+}
+
+func TestPrintInventoryText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printInventory(&buf, newConfigInventory(jsonRun{}, testConfigKeys()), "text"); err != nil {
+		t.Fatalf("printInventory() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`env "DATABASE_URL": 2 read(s), 0 definition(s), documented`,
+		"\tread at migrate.go:8:14",
+		`flag "verbose": 0 read(s), 1 definition(s)` + "\n",
+		"\tdefined at main.go:10:25",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("inventory should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintInventoryJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printInventory(&buf, newConfigInventory(jsonRun{Path: "./..."}, testConfigKeys()), "json"); err != nil {
+		t.Fatalf("printInventory() error = %v", err)
+	}
+
+	var inventory configInventory
+	if err := json.Unmarshal(buf.Bytes(), &inventory); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if inventory.Version != jsonReportVersion || inventory.Run.Path != "./..." || len(inventory.Keys) != 2 {
+		t.Fatalf("inventory = %+v", inventory)
+	}
+	if got := inventory.Keys[0].Reads[1]; got != (jsonPosition{Filename: "migrate.go", Line: 8, Column: 14}) {
+		t.Errorf("Keys[0].Reads[1] = %+v", got)
+	}
+	if inventory.Keys[1].Reads == nil || len(inventory.Keys[1].Definitions) != 1 {
+		t.Errorf("Keys[1] = %+v, want empty reads and one definition", inventory.Keys[1])
+	}
+
+	if err := printInventory(&buf, configInventory{}, "sarif"); err == nil {
+		t.Error("printInventory() should reject unsupported formats")
+	}
+}
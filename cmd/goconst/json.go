@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+	"time"
+
+	"github.com/jgautheron/goconst"
+)
+
+// jsonReportVersion is the version of the JSON report schema. It is only
+// bumped on incompatible changes, new fields may be added at any time.
+const jsonReportVersion = 1
+
+// jsonReport is the document written by -output json.
+type jsonReport struct {
+	Version int         `json:"version"`
+	Run     jsonRun     `json:"run"`
+	Issues  []jsonIssue `json:"issues"`
+	Summary jsonSummary `json:"summary"`
+}
+
+// jsonRun describes the analysis the report was produced by.
+type jsonRun struct {
+	Path       string     `json:"path"`
+	Config     jsonConfig `json:"config"`
+	DurationMS int64      `json:"duration_ms"`
+	Files      int        `json:"files"`
+	Literals   int        `json:"literals"`
+}
+
+// jsonConfig holds the options that affect which issues are reported.
+type jsonConfig struct {
+	Ignore        string   `json:"ignore,omitempty"`
+	IgnoreStrings []string `json:"ignore_strings,omitempty"`
+	IgnoreTests   bool     `json:"ignore_tests"`
+	TestFiles     []string `json:"test_files,omitempty"`
+	TestPackages  []string `json:"test_packages,omitempty"`
+	IgnoreCalls   []string `json:"ignore_calls,omitempty"`
+	IgnoreInFuncs []string `json:"ignore_in_functions,omitempty"`
+	ExcludeTypes  []string `json:"exclude_types,omitempty"`
+	// Composite literal rules
+	IgnoreComposites  []string `json:"ignore_composites,omitempty"`
+	IncludeComposites []string `json:"include_composites,omitempty"`
+	// Classifier options
+	IgnoreCategories []string `json:"ignore_categories,omitempty"`
+	OnlyCategories   []string `json:"only_categories,omitempty"`
+	CategoryPatterns []string `json:"category_patterns,omitempty"`
+	MinOccurrences   int      `json:"min_occurrences"`
+	MinLength        int      `json:"min_length"`
+	Scope            string   `json:"scope"`
+	TableTests       string   `json:"table_tests"`
+	MinFiles         int      `json:"min_files,omitempty"`
+	MinPackages      int      `json:"min_packages,omitempty"`
+	// Per-context thresholds, keyed by context name
+	MinOccurrencesByType map[string]int `json:"min_occurrences_by_type,omitempty"`
+	MinLengthByType      map[string]int `json:"min_length_by_type,omitempty"`
+	MatchConstant        bool           `json:"match_constant"`
+	TestConstants        bool           `json:"test_constants,omitempty"`
+	SentinelErrors       bool           `json:"sentinel_errors,omitempty"`
+	ConfigKeys           bool           `json:"config_keys,omitempty"`
+	ConfigReaders        []string       `json:"config_readers,omitempty"`
+	ConfigDefiners       []string       `json:"config_definers,omitempty"`
+	ConfigDocs           []string       `json:"config_docs,omitempty"`
+	ContextKeys          bool           `json:"context_keys,omitempty"`
+	Enums                bool           `json:"enums,omitempty"`
+	FindDuplicates       bool           `json:"find_duplicates"`
+	EvalConstExpr        bool           `json:"eval_const_expr"`
+	Numbers              bool           `json:"numbers"`
+	Min                  int            `json:"min,omitempty"`
+	Max                  int            `json:"max,omitempty"`
+	NewFromRev           string         `json:"new_from_rev,omitempty"`
+	NewFromPatch         string         `json:"new_from_patch,omitempty"`
+	NewStaged            bool           `json:"new_staged,omitempty"`
+	Sort                 string         `json:"sort"`
+	FailScore            float64        `json:"fail_score,omitempty"`
+}
+
+// jsonIssue is an issue as written in the report. Positions are replaced
+// by their stable representation, without the byte offset.
+type jsonIssue struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	goconst.Issue
+	Pos          jsonPosition     `json:"pos"`
+	DuplicatePos *jsonPosition    `json:"duplicate_pos,omitempty"`
+	Occurrences  []jsonOccurrence `json:"occurrences,omitempty"`
+}
+
+// jsonPosition is a position in a source file.
+type jsonPosition struct {
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// jsonOccurrence is an occurrence of a string, with its package and context.
+type jsonOccurrence struct {
+	jsonPosition
+	Package string `json:"package"`
+	Context string `json:"context"`
+}
+
+// jsonSummary counts the reported issues.
+type jsonSummary struct {
+	Issues             int            `json:"issues"`
+	Strings            int            `json:"strings"`
+	Occurrences        int            `json:"occurrences"`
+	MatchingConstants  int            `json:"matching_constants"`
+	DuplicateConstants int            `json:"duplicate_constants"`
+	Detected           map[string]int `json:"detected,omitempty"`
+}
+
+// newJSONRun describes the current run from the command-line flags.
+func newJSONRun(path string, duration time.Duration, files, literals int) jsonRun {
+	return jsonRun{
+		Path: path,
+		Config: jsonConfig{
+			Ignore:               *flagIgnore,
+			IgnoreStrings:        parseCommaSeparatedValues(*flagIgnoreStrings),
+			IgnoreTests:          *flagIgnoreTests,
+			TestFiles:            parseCommaSeparatedValues(*flagTestFiles),
+			TestPackages:         parseCommaSeparatedValues(*flagTestPackages),
+			IgnoreCalls:          parseCommaSeparatedValues(*flagIgnoreCalls),
+			IgnoreInFuncs:        parseCommaSeparatedValues(*flagIgnoreInFuncs),
+			ExcludeTypes:         parseCommaSeparatedValues(*flagExcludeTypes),
+			IgnoreComposites:     splitCompositeRules(*flagIgnoreComps),
+			IncludeComposites:    splitCompositeRules(*flagIncludeComps),
+			IgnoreCategories:     parseCommaSeparatedValues(*flagIgnoreCats),
+			OnlyCategories:       parseCommaSeparatedValues(*flagOnlyCats),
+			CategoryPatterns:     parseCommaSeparatedValues(*flagCatPatterns),
+			MinOccurrences:       *flagMinOccurrences,
+			MinLength:            *flagMinLength,
+			Scope:                *flagScope,
+			TableTests:           *flagTableTests,
+			MinFiles:             *flagMinFiles,
+			MinPackages:          *flagMinPackages,
+			MinOccurrencesByType: thresholdsByName(*flagMinOccByType),
+			MinLengthByType:      thresholdsByName(*flagMinLenByType),
+			MatchConstant:        *flagMatchConstant,
+			TestConstants:        *flagTestConstants,
+			SentinelErrors:       *flagSentinelErrors,
+			ConfigKeys:           *flagConfigKeys,
+			ConfigReaders:        parseCommaSeparatedValues(*flagConfigReaders),
+			ConfigDefiners:       parseCommaSeparatedValues(*flagConfigDefiners),
+			ConfigDocs:           parseCommaSeparatedValues(*flagConfigDocs),
+			ContextKeys:          *flagContextKeys,
+			Enums:                *flagEnums,
+			FindDuplicates:       *flagFindDuplicates,
+			EvalConstExpr:        *flagEvalConstExpr,
+			Numbers:              *flagNumbers,
+			Min:                  *flagMin,
+			Max:                  *flagMax,
+			NewFromRev:           *flagNewFromRev,
+			NewFromPatch:         *flagNewFromPatch,
+			NewStaged:            *flagNewStaged,
+			Sort:                 *flagSort,
+			FailScore:            *flagFailScore,
+		},
+		DurationMS: duration.Milliseconds(),
+		Files:      files,
+		Literals:   literals,
+	}
+}
+
+// thresholdsByName parses per-context thresholds and keys them by context name.
+func thresholdsByName(input string) map[string]int {
+	thresholds, err := parseTypeThresholds(input)
+	if err != nil || len(thresholds) == 0 {
+		return nil
+	}
+	byName := make(map[string]int, len(thresholds))
+	for typ, n := range thresholds {
+		byName[typ.String()] = n
+	}
+	return byName
+}
+
+// newJSONPosition converts a token position, dropping its byte offset.
+func newJSONPosition(pos token.Position) jsonPosition {
+	return jsonPosition{Filename: pos.Filename, Line: pos.Line, Column: pos.Column}
+}
+
+// printJSON writes the versioned JSON report: the run metadata, one entry
+// per issue and a summary of the issues.
+func printJSON(out io.Writer, meta jsonRun, issues []goconst.Issue) error {
+	report := jsonReport{
+		Version: jsonReportVersion,
+		Run:     meta,
+		Issues:  make([]jsonIssue, 0, len(issues)),
+	}
+
+	for _, group := range groupIssues(issues) {
+		if group.Rule == ruleDuplicateConstant {
+			report.Summary.DuplicateConstants++
+			continue
+		}
+		if kind := group.Issues[0].Kind; kind != "" {
+			if report.Summary.Detected == nil {
+				report.Summary.Detected = map[string]int{}
+			}
+			report.Summary.Detected[kind]++
+			continue
+		}
+		report.Summary.Strings++
+		report.Summary.Occurrences += group.Count
+		if len(group.Constants) > 0 {
+			report.Summary.MatchingConstants++
+		}
+	}
+
+	for _, issue := range issues {
+		entry := jsonIssue{
+			Rule:    issueRule(issue),
+			Message: issueMessage(issue),
+			Issue:   issue,
+			Pos:     newJSONPosition(issue.Pos),
+		}
+		if issue.DuplicatePos.IsValid() {
+			dup := newJSONPosition(issue.DuplicatePos)
+			entry.DuplicatePos = &dup
+		}
+		for _, occ := range issue.Occurrences {
+			entry.Occurrences = append(entry.Occurrences, jsonOccurrence{
+				jsonPosition: newJSONPosition(occ.Position),
+				Package:      occ.PackageName(),
+				Context:      occ.Context.String(),
+			})
+		}
+		report.Issues = append(report.Issues, entry)
+	}
+	report.Summary.Issues = len(report.Issues)
+
+	return json.NewEncoder(out).Encode(report)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jgautheron/goconst"
+)
+
+func TestPrintJSON(t *testing.T) {
+	var buf bytes.Buffer
+	meta := jsonRun{Path: "./...", Config: jsonConfig{MinOccurrences: 2, MinLength: 3}, DurationMS: 12, Files: 4}
+	if err := printJSON(&buf, meta, testIssues()); err != nil {
+		t.Fatalf("printJSON() error = %v", err)
+	}
+
+	if strings.Contains(buf.String(), "Offset") {
+		t.Errorf("report should not expose token.Position internals:\n%s", buf.String())
+	}
+
+	var report jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if report.Version != jsonReportVersion {
+		t.Errorf("Version = %d, want %d", report.Version, jsonReportVersion)
+	}
+	if report.Run.Path != "./..." || report.Run.Files != 4 || report.Run.DurationMS != 12 {
+		t.Errorf("Run = %+v, want the given metadata", report.Run)
+	}
+	if len(report.Issues) != 4 {
+		t.Fatalf("got %d issues, want 4", len(report.Issues))
+	}
+
+	first := report.Issues[0]
+	if first.Rule != ruleRepeatedString || first.Str != "foo" || first.OccurrencesCount != 3 {
+		t.Errorf("first issue = %+v", first)
+	}
+	if first.Pos != (jsonPosition{Filename: "a.go", Line: 3, Column: 2}) {
+		t.Errorf("first issue position = %+v", first.Pos)
+	}
+	if len(first.Occurrences) != 3 || first.Occurrences[0].Context != "assignment" {
+		t.Errorf("first issue occurrences = %+v", first.Occurrences)
+	}
+	if first.DuplicatePos != nil {
+		t.Errorf("string issue should not have a duplicate position, got %+v", first.DuplicatePos)
+	}
+
+	if got := report.Issues[2]; got.Rule != ruleMatchingConstant || got.MatchingConst != "Foo" {
+		t.Errorf("test-scope issue = %+v", got)
+	}
+
+	dup := report.Issues[3]
+	if dup.Rule != ruleDuplicateConstant || dup.DuplicateConst != "Bar" || dup.DuplicatePos == nil || dup.DuplicatePos.Line != 2 {
+		t.Errorf("duplicate constant issue = %+v", dup)
+	}
+
+	want := jsonSummary{Issues: 4, Strings: 2, Occurrences: 5, MatchingConstants: 1, DuplicateConstants: 1}
+	if !reflect.DeepEqual(report.Summary, want) {
+		t.Errorf("Summary = %+v, want %+v", report.Summary, want)
+	}
+
+	buf.Reset()
+	if err := printJSON(&buf, meta, append(testIssues(), testDetectorIssues()...)); err != nil {
+		t.Fatalf("printJSON() error = %v", err)
+	}
+	report = jsonReport{}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	want.Issues = 6
+	want.Detected = map[string]int{"sentinel-error": 1, "error-comparison": 1}
+	if !reflect.DeepEqual(report.Summary, want) {
+		t.Errorf("Summary with detector findings = %+v, want %+v", report.Summary, want)
+	}
+}
+
+func TestPrintJSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printJSON(&buf, newJSONRun(".", time.Second, 0, 0), nil); err != nil {
+		t.Fatalf("printJSON() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"issues":[]`) {
+		t.Errorf("empty report should have an empty issue list, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"duration_ms":1000`) {
+		t.Errorf("duration should be reported in milliseconds, got:\n%s", buf.String())
+	}
+}
+
+func TestIssueMessageTableTest(t *testing.T) {
+	issue := goconst.Issue{Str: "valid input", OccurrencesCount: 2, MatchingConst: "Valid", TableTest: true}
+	want := `2 occurrence(s) of "valid input" found in table-driven test cases, a matching constant has been found: Valid`
+	if got := issueMessage(issue); got != want {
+		t.Errorf("issueMessage() = %q, want %q", got, want)
+	}
+}
@@ -1,13 +1,14 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jgautheron/goconst"
 )
@@ -23,18 +24,82 @@ Flags:
   -ignore            exclude files matching the given regular expression
   -ignore-strings    exclude strings matching the given regular expression
   -ignore-tests      exclude tests from the search (default: true)
+  -test-files        also treat the files matching these patterns as tests (comma separated,
+                     e.g. *_mock.go,internal/testutil,e2e,fakes)
+  -test-packages     also treat the packages matching these names as tests (comma separated,
+                     e.g. testutil,*test)
   -min-occurrences   report from how many occurrences (default: 2)
   -min-length        only report strings with the minimum given length (default: 3)
+  -scope             count occurrences per module, package, file or function (default: module)
+  -min-files         only report strings found in at least this many files within their scope
+  -min-packages      only report strings found in at least this many packages within their scope
+  -table-tests       how to count the cases of table-driven tests: include them with the other
+                     test literals, exclude them, or count them separately and only report
+                     values repeated across tables (default: include)
   -match-constant    look for existing constants matching the strings
+  -test-constants    report test literals equal to a production constant the test could use,
+                     even below -min-occurrences (needs -ignore-tests=false)
+  -sentinel-errors   report error messages repeated in errors.New and fmt.Errorf calls, and
+                     comparisons of err.Error() with literals, suggesting sentinel errors
+  -config-keys       report configuration keys (os.Getenv, flag.String, ...) read in several
+                     places, or read but never defined nor documented
+  -config-readers    also collect the keys read by these accessors (comma separated, e.g.
+                     viper.Get*); NAME#N takes the key from argument N
+  -config-definers   also collect the keys defined by these accessors (e.g. viper.SetDefault)
+  -config-docs       files documenting the configuration keys (comma separated, e.g.
+                     README.md,.env.example)
+  -config-inventory  print the configuration keys with their locations instead of the issues,
+                     with -output text or json
+  -context-keys      report every literal used as a context value key in context.WithValue
+                     and ctx.Value, suggesting an unexported key type
+  -enums             report variables, fields and named string types compared with a set of
+                     literals in several places, suggesting an enum type with a const block
   -find-duplicates   look for constants with identical values
   -eval-const-expr   enable evaluation of constant expressions (e.g., Prefix + "suffix")
-  -ignore-calls      ignore string literals in calls to these functions (comma separated)
+  -ignore-calls      ignore string literals in calls to these functions (comma separated, e.g.
+                     slog.Info,log/slog.Info,(*testing.T).Errorf,fmt.*); NAME#N, NAME#param
+                     and NAME#... only ignore an argument, a parameter or the variadic tail
+  -ignore-in-functions
+                     ignore string literals in the bodies of the functions matching these
+                     patterns (comma separated, e.g. Test*,Example*,init,Server.*)
+  -exclude-types     ignore literals found in these contexts (comma separated: assignment,
+                     binary, case, return, call, composite-lit)
+  -ignore-composites ignore literals in composite literals of these types, or only in the
+                     listed fields (comma separated, e.g. cobra.Command{Use,Short})
+  -include-composites
+                     consider literals in composite literals of these types or fields even
+                     when they would be ignored (comma separated, e.g. Config{Endpoint})
+  -min-occurrences-by-type
+                     per-context occurrence thresholds, e.g. case=2,call=5
+  -min-length-by-type
+                     per-context minimum lengths, e.g. case=2
+  -ignore-categories do not report literals of these categories (comma separated: url, path, sql,
+                     http-header, mime-type, regexp, env-var, log-message, uuid, prose, other)
+  -only-categories   only report literals of these categories (comma separated)
+  -category-patterns add classifier rules as category=regexp pairs (comma separated),
+                     tried before the built-in ones
   -numbers           search also for duplicated numbers
   -min               minimum value, only works with -numbers
   -max               maximum value, only works with -numbers
-  -output            output formatting (text or json)
+  -output            output formatting (text, json, sarif, checkstyle, junit,
+                     github-actions, gitlab, html or markdown), all but text and
+                     github-actions take a single path
+  -output-file       write the results to the given file instead of standard output
+  -markdown-top      number of literals listed in the markdown summary table (default: 10)
+  -markdown-max-size maximum size in bytes of the markdown output, 0 for unlimited (default: 65000)
+  -stats             print a summary of the run instead of the issues, with -output text or json
+  -stats-top         number of literals, packages and files listed by -stats (default: 10)
   -set-exit-status   Set exit status to 2 if any issues are found
+  -fail-score        with -set-exit-status, only fail on issues scoring at least this much
+  -sort              order of the issues: position (by string and position) or score (highest first)
   -grouped           print single line per match, only works with -output text
+  -format            Go text/template evaluated per issue, replaces -output
+  -format-file       like -format, but the template is read from the given file
+  -new-from-rev      only report issues with an occurrence in lines changed since the given git revision,
+                     or in untracked Go files
+  -new-from-patch    only report issues with an occurrence in lines added by the given unified diff file
+  -new-staged        only report issues with an occurrence in lines staged in git (pre-commit);
+                     the three -new-* flags are mutually exclusive
 
 Examples:
 
@@ -45,26 +110,91 @@ Examples:
   goconst -min-occurrences 5 $(go list -m -f '{{.Dir}}')
   goconst -eval-const-expr -match-constant . # Matches constant expressions like Prefix + "suffix"
   goconst -ignore-calls slog.Info,slog.Warn,fmt.Errorf ./... # Ignore strings in logging/error calls
+  goconst -ignore-calls '(*go.uber.org/zap.Logger).*' ./... # Ignore strings in every zap.Logger method call
+  goconst -ignore-calls 'slog.Info#msg,#format' ./... # Ignore log messages and format strings, not attribute values
+  goconst -min-occurrences-by-type case=2,call=5 ./... # Cases are worth a constant sooner than call arguments
+  goconst -ignore-tests=false -ignore-in-functions 'Test*,Benchmark*' ./... # Test helpers, not test bodies
+  goconst -ignore-composites 'cobra.Command{Use,Short,Long}' -include-composites 'Config{Endpoint}' ./...
+  goconst -ignore-categories log-message,prose ./... # Skip messages meant for humans
+  goconst -scope package ./... # Only flag duplication within a package
+  goconst -ignore-tests=false -table-tests separate -match-constant ./... # Test cases repeated across tables
+  goconst -ignore-tests=false -test-constants ./... # Tests hard-coding the value of a production constant
+  goconst -sentinel-errors ./... # Error messages that should be sentinel errors checked with errors.Is
+  goconst -config-keys -config-readers 'viper.Get*' -config-docs .env.example ./... # Config key drift
+  goconst -config-inventory -config-readers 'viper.Get*' -output json ./... > config-keys.json
+  goconst -context-keys ./... # String context keys that may collide across packages
+  goconst -enums ./... # Status fields compared with "active", "deleted"... across the codebase
+  goconst -test-files '*_mock.go,internal/testutil,e2e' ./... # Keep test helpers out of production findings
+  goconst -new-from-rev main ./... # Only report duplication introduced since main
+  goconst -output sarif ./... > goconst.sarif # Upload to a code-scanning dashboard
+  goconst -output junit ./... > goconst-junit.xml # Show findings as failed tests in Jenkins
+  goconst -output github-actions ./... # Annotate pull requests from a GitHub Actions step
+  goconst -output gitlab ./... > gl-code-quality-report.json # GitLab Code Quality artifact
+  goconst -output html -output-file goconst.html ./... # Standalone report to archive as a CI artifact
+  goconst -output markdown -new-from-rev main ./... # Pull-request comment body
+  goconst -sort score -grouped ./... | head -n 10 # The ten findings to fix first
+  goconst -set-exit-status -fail-score 20 ./... # Only fail CI on high-priority duplication
+  goconst -stats -output json ./... > goconst-stats.json # Tech-debt review numbers
+  goconst -format '{{.Pos.Filename}}:{{.Pos.Line}}:{{.Pos.Column}}: {{.Message}}' ./... # vim quickfix
 `
 
 var (
-	flagIgnore         = flag.String("ignore", "", "ignore files matching the given regular expression")
-	flagIgnoreStrings  = flag.String("ignore-strings", "", "ignore strings matching the given regular expressions (comma separated)")
-	flagIgnoreTests    = flag.Bool("ignore-tests", true, "exclude tests from the search")
-	flagMinOccurrences = flag.Int("min-occurrences", 2, "report from how many occurrences")
-	flagMinLength      = flag.Int("min-length", 3, "only report strings with the minimum given length")
-	flagMatchConstant  = flag.Bool("match-constant", false, "look for existing constants matching the strings")
-	flagFindDuplicates = flag.Bool("find-duplicates", false, "look for constants with duplicated values")
-	flagEvalConstExpr  = flag.Bool("eval-const-expr", false, "enable evaluation of constant expressions (e.g., Prefix + \"suffix\")")
-	flagNumbers        = flag.Bool("numbers", false, "search also for duplicated numbers")
-	flagMin            = flag.Int("min", 0, "minimum value, only works with -numbers")
-	flagMax            = flag.Int("max", 0, "maximum value, only works with -numbers")
-	flagOutput         = flag.String("output", "text", "output formatting")
-	flagSetExitStatus  = flag.Bool("set-exit-status", false, "Set exit status to 2 if any issues are found")
-	flagGrouped        = flag.Bool("grouped", false, "print single line per match, only works with -output text")
-	flagIgnoreCalls    = flag.String("ignore-calls", "", "ignore string literals in calls to these functions (comma separated, e.g. slog.Info,fmt.Errorf)")
+	flagIgnore          = flag.String("ignore", "", "ignore files matching the given regular expression")
+	flagIgnoreStrings   = flag.String("ignore-strings", "", "ignore strings matching the given regular expressions (comma separated)")
+	flagIgnoreTests     = flag.Bool("ignore-tests", true, "exclude tests from the search")
+	flagTestFiles       = flag.String("test-files", "", "also treat the files matching these patterns as tests (comma separated)")
+	flagTestPackages    = flag.String("test-packages", "", "also treat the packages matching these names as tests (comma separated)")
+	flagMinOccurrences  = flag.Int("min-occurrences", 2, "report from how many occurrences")
+	flagMinLength       = flag.Int("min-length", 3, "only report strings with the minimum given length")
+	flagMatchConstant   = flag.Bool("match-constant", false, "look for existing constants matching the strings")
+	flagTestConstants   = flag.Bool("test-constants", false, "report test literals equal to a production constant, even below -min-occurrences")
+	flagSentinelErrors  = flag.Bool("sentinel-errors", false, "report repeated error messages and err.Error() comparisons, suggesting sentinel errors")
+	flagConfigKeys      = flag.Bool("config-keys", false, "report configuration keys read in several places, or never defined nor documented")
+	flagConfigReaders   = flag.String("config-readers", "", "also collect the keys read by these accessors (comma separated, e.g. viper.Get*)")
+	flagConfigDefiners  = flag.String("config-definers", "", "also collect the keys defined by these accessors (comma separated, e.g. viper.SetDefault)")
+	flagConfigDocs      = flag.String("config-docs", "", "files documenting the configuration keys (comma separated)")
+	flagConfigInventory = flag.Bool("config-inventory", false, "print the configuration keys with their locations instead of the issues")
+	flagContextKeys     = flag.Bool("context-keys", false, "report every literal used as a context value key, suggesting an unexported key type")
+	flagEnums           = flag.Bool("enums", false, "report values compared with a set of literals in several places, suggesting an enum type")
+	flagFindDuplicates  = flag.Bool("find-duplicates", false, "look for constants with duplicated values")
+	flagEvalConstExpr   = flag.Bool("eval-const-expr", false, "enable evaluation of constant expressions (e.g., Prefix + \"suffix\")")
+	flagNumbers         = flag.Bool("numbers", false, "search also for duplicated numbers")
+	flagMin             = flag.Int("min", 0, "minimum value, only works with -numbers")
+	flagMax             = flag.Int("max", 0, "maximum value, only works with -numbers")
+	flagOutput          = flag.String("output", "text", "output formatting")
+	flagSetExitStatus   = flag.Bool("set-exit-status", false, "Set exit status to 2 if any issues are found")
+	flagGrouped         = flag.Bool("grouped", false, "print single line per match, only works with -output text")
+	flagScope           = flag.String("scope", "module", "count occurrences per module, package, file or function")
+	flagTableTests      = flag.String("table-tests", "include", "how to count the cases of table-driven tests: include, exclude or separate")
+	flagMinFiles        = flag.Int("min-files", 0, "only report strings found in at least this many files within their scope")
+	flagMinPackages     = flag.Int("min-packages", 0, "only report strings found in at least this many packages within their scope")
+	flagIgnoreCats      = flag.String("ignore-categories", "", "do not report literals of these categories (comma separated)")
+	flagOnlyCats        = flag.String("only-categories", "", "only report literals of these categories (comma separated)")
+	flagCatPatterns     = flag.String("category-patterns", "", "add classifier rules as category=regexp pairs (comma separated)")
+	flagExcludeTypes    = flag.String("exclude-types", "", "ignore literals found in these contexts (comma separated)")
+	flagMinOccByType    = flag.String("min-occurrences-by-type", "", "per-context occurrence thresholds (e.g. case=2,call=5)")
+	flagMinLenByType    = flag.String("min-length-by-type", "", "per-context minimum lengths (e.g. case=2)")
+	flagIgnoreCalls     = flag.String("ignore-calls", "", "ignore string literals in calls to these functions (comma separated, e.g. slog.Info,(*testing.T).Errorf,fmt.*)")
+	flagIgnoreInFuncs   = flag.String("ignore-in-functions", "", "ignore string literals in the bodies of the functions matching these patterns (comma separated, e.g. Test*,init,Server.*)")
+	flagIgnoreComps     = flag.String("ignore-composites", "", "ignore literals in composite literals of these types or fields (comma separated, e.g. cobra.Command{Use,Short})")
+	flagIncludeComps    = flag.String("include-composites", "", "always consider literals in composite literals of these types or fields (comma separated, e.g. Config{Endpoint})")
+	flagFormat          = flag.String("format", "", "Go text/template evaluated per issue, replaces -output")
+	flagFormatFile      = flag.String("format-file", "", "file containing a Go text/template evaluated per issue")
+	flagNewFromRev      = flag.String("new-from-rev", "", "only report issues with an occurrence in lines changed since the given git revision")
+	flagNewFromPatch    = flag.String("new-from-patch", "", "only report issues with an occurrence in lines added by the given unified diff file")
+	flagNewStaged       = flag.Bool("new-staged", false, "only report issues with an occurrence in lines staged in git")
+	flagOutputFile      = flag.String("output-file", "", "write the results to the given file instead of standard output")
+	flagMarkdownTop     = flag.Int("markdown-top", 10, "number of literals listed in the markdown summary table")
+	flagMarkdownMaxSize = flag.Int("markdown-max-size", 65000, "maximum size in bytes of the markdown output, 0 for unlimited")
+	flagStats           = flag.Bool("stats", false, "print a summary of the run instead of the issues")
+	flagStatsTop        = flag.Int("stats-top", 10, "number of literals, packages and files listed by -stats")
+	flagSort            = flag.String("sort", "position", "order of the issues: position or score")
+	flagFailScore       = flag.Float64("fail-score", 0, "with -set-exit-status, only fail on issues scoring at least this much")
 )
 
+// output receives the results, standard output is used when nil.
+var output io.Writer
+
 func main() {
 	flag.Usage = func() {
 		usage(os.Stderr)
@@ -77,6 +207,19 @@ func main() {
 		usage(os.Stderr)
 		os.Exit(1)
 	}
+	if err := checkPaths(args); err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+
+	if *flagOutputFile != "" {
+		f, err := os.Create(*flagOutputFile)
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		output = f
+	}
 
 	lintFailed := false
 	for _, path := range args {
@@ -91,11 +234,35 @@ func main() {
 		}
 	}
 
+	if f, ok := output.(*os.File); ok {
+		if err := f.Close(); err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+	}
+
 	if lintFailed && *flagSetExitStatus {
 		os.Exit(2)
 	}
 }
 
+// checkPaths rejects several paths when the output is a single document,
+// which one run per path would write several times.
+func checkPaths(paths []string) error {
+	if len(paths) < 2 || *flagFormat != "" || *flagFormatFile != "" {
+		return nil
+	}
+	switch {
+	case *flagStats && *flagOutput != "text":
+		return fmt.Errorf("-stats -output %s writes a single document, analyze a single path such as ./...", *flagOutput)
+	case *flagConfigInventory && *flagOutput != "text":
+		return fmt.Errorf("-config-inventory -output %s writes a single document, analyze a single path such as ./...", *flagOutput)
+	case *flagOutput != "text" && *flagOutput != "github-actions":
+		return fmt.Errorf("-output %s writes a single document, analyze a single path such as ./...", *flagOutput)
+	}
+	return nil
+}
+
 // run analyzes a single path for repeated strings that could be constants.
 // It returns true if any issues were found, and an error if the analysis failed.
 func run(path string) (bool, error) {
@@ -106,6 +273,39 @@ func run(path string) (bool, error) {
 		ignoreStrings = parseCommaSeparatedValues(*flagIgnoreStrings)
 	}
 
+	excludeTypes := map[goconst.Type]bool{}
+	for _, name := range parseCommaSeparatedValues(*flagExcludeTypes) {
+		typ, err := goconst.ParseType(strings.TrimSpace(name))
+		if err != nil {
+			return false, err
+		}
+		excludeTypes[typ] = true
+	}
+
+	scope, err := goconst.ParseScope(*flagScope)
+	if err != nil {
+		return false, err
+	}
+
+	tableTests, err := goconst.ParseTableTestMode(*flagTableTests)
+	if err != nil {
+		return false, err
+	}
+
+	categoryPatterns, err := parseCategoryPatterns(*flagCatPatterns)
+	if err != nil {
+		return false, err
+	}
+
+	minOccurrencesByType, err := parseTypeThresholds(*flagMinOccByType)
+	if err != nil {
+		return false, err
+	}
+	minLengthByType, err := parseTypeThresholds(*flagMinLenByType)
+	if err != nil {
+		return false, err
+	}
+
 	gco := goconst.NewWithIgnorePatterns(
 		path,
 		*flagIgnore,
@@ -119,19 +319,96 @@ func run(path string) (bool, error) {
 		*flagMax,
 		*flagMinLength,
 		*flagMinOccurrences,
-		map[goconst.Type]bool{},
+		excludeTypes,
 	)
+	gco.SetMinOccurrencesByType(minOccurrencesByType)
+	gco.SetMinLengthByType(minLengthByType)
+	gco.SetScope(scope)
+	gco.SetTableTests(tableTests)
+	gco.SetTestConstants(*flagTestConstants)
+	gco.SetSentinelErrors(*flagSentinelErrors)
+	gco.SetConfigKeys(*flagConfigKeys || *flagConfigInventory,
+		parseCommaSeparatedValues(*flagConfigReaders), parseCommaSeparatedValues(*flagConfigDefiners))
+	gco.SetContextKeys(*flagContextKeys)
+	gco.SetEnums(*flagEnums)
+	if err := gco.SetConfigKeyDocs(parseCommaSeparatedValues(*flagConfigDocs)); err != nil {
+		return false, err
+	}
+	gco.SetMinSpread(*flagMinFiles, *flagMinPackages)
+	if err := gco.SetIgnoreInFunctions(parseCommaSeparatedValues(*flagIgnoreInFuncs)); err != nil {
+		return false, err
+	}
+	gco.SetCategoryFilters(parseCommaSeparatedValues(*flagIgnoreCats), parseCommaSeparatedValues(*flagOnlyCats))
+	if err := gco.SetCategoryPatterns(categoryPatterns); err != nil {
+		return false, err
+	}
+
+	if err := gco.SetTestPatterns(parseCommaSeparatedValues(*flagTestFiles), parseCommaSeparatedValues(*flagTestPackages)); err != nil {
+		return false, err
+	}
+	if err := gco.SetCompositeRules(splitCompositeRules(*flagIgnoreComps), splitCompositeRules(*flagIncludeComps)); err != nil {
+		return false, err
+	}
 
 	if *flagIgnoreCalls != "" {
 		gco.SetIgnoreFunctions(parseCommaSeparatedValues(*flagIgnoreCalls))
 	}
 
-	strs, consts, err := gco.ParseTree()
+	if *flagSort != "position" && *flagSort != "score" {
+		return false, fmt.Errorf("unsupported sort order: %s", *flagSort)
+	}
+
+	tmpl, err := loadTemplate()
+	if err != nil {
+		return false, err
+	}
+
+	// Occurrences are counted across the whole tree, but only issues
+	// touching a changed line are reported.
+	changes, err := loadChangedLines()
 	if err != nil {
 		return false, err
 	}
 
-	return printOutput(strs, consts, *flagOutput)
+	start := time.Now()
+	if _, _, err := gco.ParseTree(); err != nil {
+		return false, err
+	}
+
+	issues := gco.Issues()
+	if changes != nil {
+		issues = changes.filter(issues)
+	}
+	sortIssues(issues, *flagSort)
+
+	// Every issue is reported, but only those reaching -fail-score
+	// make the run fail.
+	failed := failingIssues(issues, *flagFailScore)
+
+	if tmpl != nil {
+		return failed, printTemplate(outputWriter(), tmpl, issues)
+	}
+
+	meta := newJSONRun(path, time.Since(start), gco.FileCount(), gco.LiteralCount())
+	if *flagConfigInventory {
+		return failed, printInventory(outputWriter(), newConfigInventory(meta, gco.ConfigKeys()), *flagOutput)
+	}
+	if *flagStats {
+		return failed, printStats(outputWriter(), computeStats(meta, issues, *flagStatsTop), *flagOutput)
+	}
+
+	if _, err := printOutput(meta, issues, *flagOutput); err != nil {
+		return false, err
+	}
+	return failed, nil
+}
+
+// outputWriter returns the writer results are printed to.
+func outputWriter() io.Writer {
+	if output != nil {
+		return output
+	}
+	return os.Stdout
 }
 
 // parseCommaSeparatedValues splits a comma-separated string into a slice of strings,
@@ -178,6 +455,67 @@ func parseCommaSeparatedValues(input string) []string {
 	return result
 }
 
+// parseTypeThresholds parses comma-separated context=value pairs, such as
+// "case=2,call=5".
+func parseTypeThresholds(input string) (map[goconst.Type]int, error) {
+	thresholds := map[goconst.Type]int{}
+	for _, pair := range parseCommaSeparatedValues(input) {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid threshold %q, expected context=value", pair)
+		}
+		typ, err := goconst.ParseType(name)
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid threshold %q, expected a non-negative number", pair)
+		}
+		thresholds[typ] = n
+	}
+	return thresholds, nil
+}
+
+// splitCompositeRules splits a comma separated list of composite literal
+// rules, leaving the commas between the braces of a field list alone.
+func splitCompositeRules(input string) []string {
+	var rules []string
+	depth, start := 0, 0
+	for i, char := range input {
+		switch char {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				rules = append(rules, input[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(input) {
+		rules = append(rules, input[start:])
+	}
+	return rules
+}
+
+// parseCategoryPatterns parses comma-separated category=regexp pairs.
+// A category may be given several patterns.
+func parseCategoryPatterns(input string) (map[string][]string, error) {
+	patterns := map[string][]string{}
+	for _, pair := range parseCommaSeparatedValues(input) {
+		category, pattern, ok := strings.Cut(pair, "=")
+		category = strings.TrimSpace(category)
+		if !ok || category == "" || pattern == "" {
+			return nil, fmt.Errorf("invalid category pattern %q, expected category=regexp", pair)
+		}
+		patterns[category] = append(patterns[category], pattern)
+	}
+	return patterns, nil
+}
+
 // usage prints the usage documentation to the specified writer.
 func usage(out io.Writer) {
 	if _, err := fmt.Fprint(out, usageDoc); err != nil {
@@ -186,69 +524,100 @@ func usage(out io.Writer) {
 }
 
 // printOutput formats and displays the analysis results based on the specified output format.
+// Every format is built on the sorted issue list, which carries the per-scope
+// (test or non-test) occurrence counts and matching constants.
 // It returns true if any issues were found, and an error if output formatting failed.
-func printOutput(strs goconst.Strings, consts goconst.Constants, output string) (bool, error) {
+func printOutput(meta jsonRun, issues []goconst.Issue, output string) (bool, error) {
+	out := outputWriter()
+
+	var err error
 	switch output {
 	case "json":
-		enc := json.NewEncoder(os.Stdout)
-		err := enc.Encode(struct {
-			Strings   goconst.Strings   `json:"strings"`
-			Constants goconst.Constants `json:"constants"`
-		}{
-			Strings:   strs,
-			Constants: consts,
-		})
-		if err != nil {
-			return false, err
-		}
+		err = printJSON(out, meta, issues)
+	case "sarif":
+		err = printSARIF(out, issues)
+	case "checkstyle":
+		err = printCheckstyle(out, issues)
+	case "junit":
+		err = printJUnit(out, issues)
+	case "github-actions":
+		err = printGitHubActions(out, issues)
+	case "gitlab":
+		err = printGitLab(out, issues)
+	case "html":
+		err = printHTML(out, issues)
+	case "markdown":
+		err = printMarkdown(out, issues, *flagMarkdownTop, *flagMarkdownMaxSize)
 	case "text":
-		for str, item := range strs {
-			for _, xpos := range item {
-				fmt.Printf(
-					`%s:%d:%d:%d other occurrence(s) of %q found in: %s`,
-					xpos.Filename,
-					xpos.Line,
-					xpos.Column,
-					len(item)-1,
-					str,
-					occurrences(item, xpos),
-				)
-				fmt.Print("\n")
-
-				if *flagGrouped {
-					break
+		printText(out, issues, *flagGrouped)
+	default:
+		return false, fmt.Errorf("unsupported output format: %s", output)
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(issues) > 0, nil
+}
+
+// printText prints one line per issue, followed by the matching constant of
+// each string and the declarations of each duplicated constant value.
+// When grouped, only the first issue of each string is printed.
+func printText(out io.Writer, issues []goconst.Issue, grouped bool) {
+	for _, group := range groupIssues(issues) {
+		if group.Rule == ruleDuplicateConstant {
+			fmt.Fprintf(out, "Duplicate constant(s) with value %q have been found:\n", group.Str)
+			fmt.Fprintf(out, "\t%s: %s\n", group.Locations[0].Position, group.Constants[0])
+			for _, loc := range group.Locations[1:] {
+				fmt.Fprintf(out, "\t%s: duplicate of %s\n", loc.Position, group.Constants[0])
+			}
+			continue
+		}
+
+		if group.Issues[0].Kind != "" {
+			for _, issue := range group.Issues {
+				fmt.Fprintf(out, "%s:%d:%d:%s\n", issue.Pos.Filename, issue.Pos.Line, issue.Pos.Column, issueMessage(issue))
+				if issue.Kind == goconst.KindEnum && len(issue.Occurrences) > 1 {
+					fmt.Fprintf(out, "\tother site(s): %s\n", occurrences(issue.Occurrences, goconst.ExtendedPos{Position: issue.Pos}))
 				}
 			}
+			continue
+		}
 
-			if len(consts) == 0 {
+		for _, issue := range group.Issues {
+			// Test literals equal to a production constant are
+			// reported alone
+			if issue.OccurrencesCount <= 1 {
+				fmt.Fprintf(out, "%s:%d:%d:single occurrence of %q found\n", issue.Pos.Filename, issue.Pos.Line, issue.Pos.Column, issue.Str)
 				continue
 			}
-			if csts, ok := consts[str]; ok && len(csts) > 0 {
-				// const should be in the same package and exported
-				fmt.Printf(`A matching constant has been found for %q: %s`, str, csts[0].Name)
-				fmt.Printf("\n\t%s\n", csts[0].String())
+
+			fmt.Fprintf(out,
+				`%s:%d:%d:%d other occurrence(s) of %q found in: %s`,
+				issue.Pos.Filename,
+				issue.Pos.Line,
+				issue.Pos.Column,
+				issue.OccurrencesCount-1,
+				issue.Str,
+				occurrences(issue.Occurrences, goconst.ExtendedPos{Position: issue.Pos}),
+			)
+			fmt.Fprint(out, "\n")
+
+			if grouped {
+				break
 			}
 		}
-		for val, csts := range consts {
-			if len(csts) > 1 {
-				fmt.Printf("Duplicate constant(s) with value %q have been found:\n", val)
 
-				for i := 0; i < len(csts); i++ {
-					fmt.Printf("\t%s: %s\n", csts[i].String(), csts[i].Name)
-				}
-			}
+		if len(group.Constants) > 0 {
+			fmt.Fprintf(out, "A matching constant has been found for %q: %s\n", group.Str, group.Constants[0])
 		}
-	default:
-		return false, fmt.Errorf("unsupported output format: %s", output)
 	}
-	return len(strs)+len(consts) > 0, nil
 }
 
 // occurrences formats a list of all occurrences of a string, excluding the current position.
 func occurrences(item []goconst.ExtendedPos, current goconst.ExtendedPos) string {
 	occurrences := []string{}
 	for _, xpos := range item {
-		if xpos == current {
+		if xpos.Position == current.Position {
 			continue
 		}
 		occurrences = append(occurrences, fmt.Sprintf(
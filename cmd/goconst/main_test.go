@@ -242,9 +242,10 @@ func test() {
 
 		// Check for expected JSON elements
 		expectedPatterns := []string{
-			`"strings"`,
-			`"should_be_constant"`,
-			`"constants"`,
+			`"version":1`,
+			`"issues"`,
+			`"str":"should_be_constant"`,
+			`"summary"`,
 		}
 
 		for _, pattern := range expectedPatterns {
@@ -402,7 +403,7 @@ func TestOccurrences(t *testing.T) {
 
 func TestPrintOutput_EmptyMaps(t *testing.T) {
 	t.Run("text empty", func(t *testing.T) {
-		hasIssues, err := printOutput(goconst.Strings{}, goconst.Constants{}, "text")
+		hasIssues, err := printOutput(jsonRun{}, nil, "text")
 		if err != nil {
 			t.Fatalf("printOutput() error = %v", err)
 		}
@@ -423,7 +424,7 @@ func TestPrintOutput_EmptyMaps(t *testing.T) {
 			_ = r.Close()
 		}()
 
-		hasIssues, err := printOutput(nil, nil, "json")
+		hasIssues, err := printOutput(jsonRun{}, nil, "json")
 		if closeErr := w.Close(); closeErr != nil {
 			t.Fatalf("failed to close writer: %v", closeErr)
 		}
@@ -439,3 +440,661 @@ func TestPrintOutput_EmptyMaps(t *testing.T) {
 		}
 	})
 }
+
+// writeFiles writes the source files, keyed by their slash-separated path,
+// into a temporary directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// runCapture runs the command on the path with the current flags and
+// returns whether it failed along with what it printed.
+func runCapture(t *testing.T, path string) (bool, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	oldOutput := output
+	output = &buf
+	defer func() {
+		output = oldOutput
+	}()
+
+	failed, err := run(path)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	return failed, buf.String()
+}
+
+func TestTextOutputDeterministicAndScoped(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"a.go": `package test
+func a() { _ = "scoped"; _ = "scoped" }`,
+		"b.go": `package test
+func b() { _ = "scoped"; _ = "other1"; _ = "other1" }`,
+		"c_test.go": `package test
+func c() { _ = "scoped"; _ = "scoped" }`,
+	})
+
+	oldIgnoreTests := *flagIgnoreTests
+	*flagIgnoreTests = false
+	defer func() {
+		*flagIgnoreTests = oldIgnoreTests
+	}()
+
+	_, first := runCapture(t, tempDir)
+	for i := 0; i < 5; i++ {
+		if _, got := runCapture(t, tempDir); got != first {
+			t.Fatalf("output differs between runs:\n%s\nvs\n%s", first, got)
+		}
+	}
+
+	a := filepath.Join(tempDir, "a.go")
+	b := filepath.Join(tempDir, "b.go")
+	c := filepath.Join(tempDir, "c_test.go")
+	want := strings.Join([]string{
+		b + `:2:30:1 other occurrence(s) of "other1" found in: ` + b + ":2:44",
+		a + `:2:16:2 other occurrence(s) of "scoped" found in: ` + a + ":2:30 " + b + ":2:16",
+		b + `:2:16:2 other occurrence(s) of "scoped" found in: ` + a + ":2:16 " + a + ":2:30",
+		c + `:2:16:1 other occurrence(s) of "scoped" found in: ` + c + ":2:30",
+		"",
+	}, "\n")
+	if first != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", first, want)
+	}
+}
+
+func TestRunScoreOptions(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"a.go": `package test
+func a() { _ = "aaa"; _ = "aaa"; _ = "zzz-spread-literal" }`,
+		"b.go": `package test
+func b() { _ = "zzz-spread-literal" }`,
+	})
+
+	oldSort, oldFailScore, oldGrouped := *flagSort, *flagFailScore, *flagGrouped
+	defer func() {
+		*flagSort, *flagFailScore, *flagGrouped = oldSort, oldFailScore, oldGrouped
+	}()
+	*flagGrouped = true
+
+	*flagSort = "position"
+	if _, out := runCapture(t, tempDir); !strings.Contains(strings.SplitN(out, "\n", 2)[0], `"aaa"`) {
+		t.Errorf("position order should list aaa first, got:\n%s", out)
+	}
+
+	*flagSort = "score"
+	if _, out := runCapture(t, tempDir); !strings.Contains(strings.SplitN(out, "\n", 2)[0], `"zzz-spread-literal"`) {
+		t.Errorf("score order should list the spread literal first, got:\n%s", out)
+	}
+
+	*flagFailScore = 1000
+	failed, out := runCapture(t, tempDir)
+	if failed {
+		t.Error("run() should not fail when no issue reaches -fail-score")
+	}
+	if !strings.Contains(out, `"aaa"`) {
+		t.Errorf("issues below -fail-score should still be reported, got:\n%s", out)
+	}
+
+	*flagSort = "random"
+	if _, err := run(tempDir); err == nil {
+		t.Error("run() should reject an unknown sort order")
+	}
+}
+
+func TestCheckPaths(t *testing.T) {
+	oldOutput, oldStats, oldFormat := *flagOutput, *flagStats, *flagFormat
+	defer func() {
+		*flagOutput, *flagStats, *flagFormat = oldOutput, oldStats, oldFormat
+	}()
+
+	tests := []struct {
+		output string
+		stats  bool
+		format string
+		paths  []string
+		ok     bool
+	}{
+		{output: "json", paths: []string{"./..."}, ok: true},
+		{output: "text", paths: []string{"a", "b"}, ok: true},
+		{output: "github-actions", paths: []string{"a", "b"}, ok: true},
+		{output: "json", format: "{{.Str}}", paths: []string{"a", "b"}, ok: true},
+		{output: "text", stats: true, paths: []string{"a", "b"}, ok: true},
+		{output: "sarif", paths: []string{"a", "b"}},
+		{output: "markdown", paths: []string{"a", "b"}},
+		{output: "json", stats: true, paths: []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		*flagOutput, *flagStats, *flagFormat = tt.output, tt.stats, tt.format
+		if err := checkPaths(tt.paths); (err == nil) != tt.ok {
+			t.Errorf("checkPaths(%q) with -output %s -stats=%v -format %q: error = %v, want ok = %v",
+				tt.paths, tt.output, tt.stats, tt.format, err, tt.ok)
+		}
+	}
+}
+
+func TestParseTypeThresholds(t *testing.T) {
+	got, err := parseTypeThresholds("case=2, call=5")
+	if err != nil {
+		t.Fatalf("parseTypeThresholds() error = %v", err)
+	}
+	if len(got) != 2 || got[goconst.Case] != 2 || got[goconst.Call] != 5 {
+		t.Errorf("parseTypeThresholds() = %v, want case=2 call=5", got)
+	}
+
+	for _, input := range []string{"case", "case=x", "case=-1", "switch=2"} {
+		if _, err := parseTypeThresholds(input); err == nil {
+			t.Errorf("parseTypeThresholds(%q) should fail", input)
+		}
+	}
+}
+
+func TestRunTypeOptions(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"f.go": `package test
+func f(s string) {
+	switch s {
+	case "in-case":
+	}
+	switch s {
+	case "in-case":
+	}
+	println("in-call")
+	println("in-call")
+}`,
+	})
+
+	oldExclude, oldByType := *flagExcludeTypes, *flagMinOccByType
+	defer func() {
+		*flagExcludeTypes, *flagMinOccByType = oldExclude, oldByType
+	}()
+
+	*flagMinOccByType = "call=3"
+	if _, out := runCapture(t, tempDir); !strings.Contains(out, "in-case") || strings.Contains(out, "in-call") {
+		t.Errorf("call threshold should hide in-call only, got:\n%s", out)
+	}
+
+	*flagMinOccByType = ""
+	*flagExcludeTypes = "case"
+	if _, out := runCapture(t, tempDir); strings.Contains(out, "in-case") || !strings.Contains(out, "in-call") {
+		t.Errorf("-exclude-types case should hide in-case only, got:\n%s", out)
+	}
+
+	*flagExcludeTypes = "switch"
+	if _, err := run(tempDir); err == nil {
+		t.Error("run() should reject unknown context types")
+	}
+}
+
+func TestRunScopeOptions(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"a.go": `package test
+func a() { _ = "across"; _ = "within"; _ = "within" }`,
+		"b.go": `package test
+func b() { _ = "across" }`,
+	})
+
+	oldScope, oldMinFiles := *flagScope, *flagMinFiles
+	defer func() {
+		*flagScope, *flagMinFiles = oldScope, oldMinFiles
+	}()
+
+	*flagScope = "file"
+	if _, out := runCapture(t, tempDir); strings.Contains(out, "across") || !strings.Contains(out, "within") {
+		t.Errorf("-scope file should only report within, got:\n%s", out)
+	}
+
+	*flagScope = "module"
+	*flagMinFiles = 2
+	if _, out := runCapture(t, tempDir); !strings.Contains(out, "across") || strings.Contains(out, "within") {
+		t.Errorf("-min-files 2 should only report across, got:\n%s", out)
+	}
+
+	*flagScope = "repository"
+	if _, err := run(tempDir); err == nil {
+		t.Error("run() should reject unknown scopes")
+	}
+}
+
+func TestParseCategoryPatterns(t *testing.T) {
+	got, err := parseCategoryPatterns(`ticket=^[A-Z]+-\d+$,ticket=^#\d+$,host=^[a-z]+\.internal$`)
+	if err != nil {
+		t.Fatalf("parseCategoryPatterns() error = %v", err)
+	}
+	if len(got["ticket"]) != 2 || len(got["host"]) != 1 {
+		t.Errorf("parseCategoryPatterns() = %v", got)
+	}
+
+	for _, input := range []string{"ticket", "=^x$", "ticket="} {
+		if _, err := parseCategoryPatterns(input); err == nil {
+			t.Errorf("parseCategoryPatterns(%q) should fail", input)
+		}
+	}
+}
+
+func TestRunCategoryOptions(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"f.go": `package test
+func f() {
+	println("https://example.com")
+	println("https://example.com")
+	println("TICKET-42")
+	println("TICKET-42")
+}`,
+	})
+
+	oldIgnore, oldOnly, oldPatterns, oldOutput := *flagIgnoreCats, *flagOnlyCats, *flagCatPatterns, *flagOutput
+	defer func() {
+		*flagIgnoreCats, *flagOnlyCats, *flagCatPatterns, *flagOutput = oldIgnore, oldOnly, oldPatterns, oldOutput
+	}()
+
+	*flagOutput = "json"
+	if _, out := runCapture(t, tempDir); !strings.Contains(out, `"category":"url"`) {
+		t.Errorf("issues should be classified without category filters, got:\n%s", out)
+	}
+
+	*flagOutput = "text"
+	*flagIgnoreCats = "url"
+	if _, out := runCapture(t, tempDir); strings.Contains(out, "example.com") || !strings.Contains(out, "TICKET-42") {
+		t.Errorf("-ignore-categories url should hide the URL only, got:\n%s", out)
+	}
+
+	*flagIgnoreCats = ""
+	*flagCatPatterns = `ticket=^[A-Z]+-\d+$`
+	*flagOnlyCats = "ticket"
+	if _, out := runCapture(t, tempDir); strings.Contains(out, "example.com") || !strings.Contains(out, "TICKET-42") {
+		t.Errorf("-only-categories ticket should report the custom category only, got:\n%s", out)
+	}
+
+	*flagCatPatterns = "bad=("
+	if _, err := run(tempDir); err == nil {
+		t.Error("run() should reject invalid category patterns")
+	}
+}
+
+func TestRunIgnoreInFunctions(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"f.go": `package test
+func init() { _ = "in-init"; _ = "in-init" }
+func f() { _ = "in-body"; _ = "in-body" }`,
+	})
+
+	oldIgnore := *flagIgnoreInFuncs
+	defer func() {
+		*flagIgnoreInFuncs = oldIgnore
+	}()
+
+	*flagIgnoreInFuncs = "init"
+	if _, out := runCapture(t, tempDir); strings.Contains(out, "in-init") || !strings.Contains(out, "in-body") {
+		t.Errorf("-ignore-in-functions init should hide in-init only, got:\n%s", out)
+	}
+
+	*flagIgnoreInFuncs = "init["
+	if _, err := run(tempDir); err == nil {
+		t.Error("run() should reject malformed patterns")
+	}
+}
+
+func TestRunIgnoreCallsResolved(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"f.go": `package test
+import (
+	"fmt"
+	str "strings"
+)
+func f(b *str.Builder) {
+	b.WriteString("written")
+	b.WriteString("written")
+	fmt.Print("printed")
+	fmt.Print("printed")
+	_ = str.ToUpper("kept")
+	_ = str.ToUpper("kept")
+}`,
+	})
+
+	oldIgnore := *flagIgnoreCalls
+	defer func() {
+		*flagIgnoreCalls = oldIgnore
+	}()
+
+	*flagIgnoreCalls = "(*strings.Builder).WriteString,fmt.*"
+	_, out := runCapture(t, tempDir)
+	if strings.Contains(out, "written") || strings.Contains(out, "printed") || !strings.Contains(out, "kept") {
+		t.Errorf("-ignore-calls should resolve methods and wildcards, got:\n%s", out)
+	}
+}
+
+func TestRunIgnoreCallsAliased(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"f.go": `package test
+import l "log/slog"
+func f() {
+	l.Info("log message here")
+	l.Info("log message here")
+}`,
+	})
+
+	oldIgnore := *flagIgnoreCalls
+	defer func() {
+		*flagIgnoreCalls = oldIgnore
+	}()
+
+	for _, rule := range []string{"slog.Info", "log/slog.Info", "slog.*"} {
+		*flagIgnoreCalls = rule
+		if _, out := runCapture(t, tempDir); strings.Contains(out, "log message here") {
+			t.Errorf("-ignore-calls %s should match the aliased import, got:\n%s", rule, out)
+		}
+	}
+}
+
+func TestRunIgnoreCallArguments(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"f.go": `package test
+import "log/slog"
+func f() {
+	slog.Info("request done", "status", "accepted")
+	slog.Info("request done", "status", "accepted")
+}`,
+	})
+
+	oldIgnore := *flagIgnoreCalls
+	defer func() {
+		*flagIgnoreCalls = oldIgnore
+	}()
+
+	*flagIgnoreCalls = "slog.Info#msg"
+	if _, out := runCapture(t, tempDir); strings.Contains(out, "request done") || !strings.Contains(out, "accepted") {
+		t.Errorf("-ignore-calls slog.Info#msg should only hide the message, got:\n%s", out)
+	}
+}
+
+func TestSplitCompositeRules(t *testing.T) {
+	got := splitCompositeRules("cobra.Command{Use,Short,Long},http.Request,Config{Endpoint}")
+	want := []string{"cobra.Command{Use,Short,Long}", "http.Request", "Config{Endpoint}"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("splitCompositeRules() = %q, want %q", got, want)
+	}
+	if got := splitCompositeRules(""); got != nil {
+		t.Errorf("splitCompositeRules(\"\") = %q, want nil", got)
+	}
+}
+
+func TestRunCompositeRules(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"f.go": `package test
+type Command struct{ Use, Short string }
+var commands = []Command{{Use: "serve", Short: "described"}, {Use: "serve", Short: "described"}}`,
+	})
+
+	oldIgnore, oldInclude := *flagIgnoreComps, *flagIncludeComps
+	defer func() {
+		*flagIgnoreComps, *flagIncludeComps = oldIgnore, oldInclude
+	}()
+
+	*flagIgnoreComps = "Command{Short,Long}"
+	if _, out := runCapture(t, tempDir); strings.Contains(out, "described") || !strings.Contains(out, "serve") {
+		t.Errorf("-ignore-composites should hide the Short fields only, got:\n%s", out)
+	}
+
+	*flagIncludeComps = "Command{"
+	if _, err := run(tempDir); err == nil {
+		t.Error("run() should reject malformed composite rules")
+	}
+}
+
+func TestRunTableTests(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"f_test.go": `package test
+func TestA(t *testing.T) {
+	for _, tt := range []struct{ name string }{{"valid input"}, {"single table"}, {"single table"}} {
+		t.Run(tt.name, nil)
+	}
+}
+func TestB(t *testing.T) {
+	for _, tt := range []struct{ name string }{{"valid input"}} {
+		t.Run(tt.name, nil)
+	}
+}`,
+	})
+
+	oldTests, oldMode := *flagIgnoreTests, *flagTableTests
+	defer func() {
+		*flagIgnoreTests, *flagTableTests = oldTests, oldMode
+	}()
+
+	*flagIgnoreTests = false
+	*flagTableTests = "separate"
+	if _, out := runCapture(t, tempDir); !strings.Contains(out, `"valid input"`) || strings.Contains(out, "single table") {
+		t.Errorf("-table-tests separate should only report values repeated across tables, got:\n%s", out)
+	}
+
+	*flagTableTests = "none"
+	if _, err := run(tempDir); err == nil {
+		t.Error("run() should reject unknown table test modes")
+	}
+}
+
+func TestRunTestConstants(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"version.go":      "package test\nconst Version = \"v3.1.0\"\n",
+		"version_test.go": "package test\nfunc TestVersion(t *testing.T) { _ = \"v3.1.0\" }\n",
+	})
+
+	oldTests, oldConstants := *flagIgnoreTests, *flagTestConstants
+	defer func() {
+		*flagIgnoreTests, *flagTestConstants = oldTests, oldConstants
+	}()
+
+	*flagIgnoreTests = false
+	*flagTestConstants = true
+	want := filepath.Join(tempDir, "version_test.go") + `:2:38:single occurrence of "v3.1.0" found
+A matching constant has been found for "v3.1.0": Version
+`
+	if _, out := runCapture(t, tempDir); out != want {
+		t.Errorf("-test-constants should report the single test literal, got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestRunTestPatterns(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"app.go":             "package test\nfunc f() { _ = \"in-production\"; _ = \"in-production\" }\n",
+		"testutil/helper.go": "package testutil\nfunc g() { _ = \"in-helper\"; _ = \"in-helper\" }\n",
+	})
+
+	oldFiles := *flagTestFiles
+	defer func() {
+		*flagTestFiles = oldFiles
+	}()
+
+	*flagTestFiles = "testutil"
+	if _, out := runCapture(t, tempDir+"/..."); strings.Contains(out, "in-helper") || !strings.Contains(out, "in-production") {
+		t.Errorf("-test-files should exclude the helpers along with the tests, got:\n%s", out)
+	}
+
+	*flagTestFiles = "[testutil"
+	if _, err := run(tempDir); err == nil {
+		t.Error("run() should reject malformed patterns")
+	}
+}
+
+func TestRunSentinelErrors(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"errors.go": `package test
+
+import "errors"
+
+func a() error { return errors.New("not found") }
+func b() error { return errors.New("not found") }
+func c(err error) bool { return err.Error() == "timeout" }
+`,
+	})
+
+	oldSentinel := *flagSentinelErrors
+	defer func() {
+		*flagSentinelErrors = oldSentinel
+	}()
+
+	*flagSentinelErrors = true
+	_, out := runCapture(t, tempDir)
+	for _, want := range []string{
+		`errors.go:5:36:2 occurrence(s) of error message "not found" found, declare a sentinel error: var ErrNotFound = errors.New("not found")`,
+		`errors.go:7:48:error message "timeout" compared through err.Error(), use errors.Is(err, ErrTimeout)`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("-sentinel-errors output should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunSentinelErrorsAliased(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"errors.go": `package test
+
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+func a() error { return stderrors.New("not found") }
+func b() error { return stderrors.New("not found") }
+func c() error { return fmt.Errorf("not found") }
+`,
+	})
+
+	oldSentinel := *flagSentinelErrors
+	defer func() {
+		*flagSentinelErrors = oldSentinel
+	}()
+
+	*flagSentinelErrors = true
+	want := `errors.go:8:39:3 occurrence(s) of error message "not found" found`
+	if _, out := runCapture(t, tempDir); !strings.Contains(out, want) {
+		t.Errorf("-sentinel-errors should resolve aliased imports, want %q, got:\n%s", want, out)
+	}
+}
+
+func TestRunConfigKeys(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"config.go": `package test
+
+import (
+	"flag"
+	"os"
+)
+
+var verbose = flag.Bool("verbose", false, "verbose output")
+
+func a() string { return os.Getenv("DATABASE_URL") }
+func b() string { return os.Getenv("DATABASE_URL") }
+func c() bool   { return flag.Lookup("dry-run") != nil }
+`,
+	})
+
+	oldKeys, oldInventory := *flagConfigKeys, *flagConfigInventory
+	defer func() {
+		*flagConfigKeys, *flagConfigInventory = oldKeys, oldInventory
+	}()
+
+	tests := []struct {
+		name      string
+		inventory bool
+		want      []string
+	}{
+		{
+			name: "issues",
+			want: []string{
+				`config.go:10:36:configuration key "DATABASE_URL" read in 2 places, declare it once: const EnvDatabaseUrl = "DATABASE_URL"`,
+				`config.go:12:38:configuration key "dry-run" is read but never defined or documented`,
+			},
+		},
+		{
+			name:      "inventory",
+			inventory: true,
+			want:      []string{`env "DATABASE_URL": 2 read(s), 0 definition(s)`, `flag "verbose": 0 read(s), 1 definition(s)`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*flagConfigKeys = !tt.inventory
+			*flagConfigInventory = tt.inventory
+			_, out := runCapture(t, tempDir)
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output should contain %q, got:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestRunContextKeys(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"keys.go": `package test
+
+import "context"
+
+func a(ctx context.Context) context.Context { return context.WithValue(ctx, "user", 1) }
+func b(ctx context.Context) any             { return ctx.Value("user") }
+`,
+	})
+
+	oldContextKeys := *flagContextKeys
+	defer func() {
+		*flagContextKeys = oldContextKeys
+	}()
+
+	*flagContextKeys = true
+	_, out := runCapture(t, tempDir)
+	for _, want := range []string{
+		`keys.go:5:77:context key "user" used in 2 place(s) may collide with other packages, use an unexported key type: type userKey struct{}`,
+		`keys.go:6:64:context key "user"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("-context-keys output should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunEnums(t *testing.T) {
+	tempDir := writeFiles(t, map[string]string{
+		"user.go": `package test
+
+type User struct{ Status string }
+
+func a(u User) bool { return u.Status == "active" }
+func b(u User) {
+	switch u.Status {
+	case "active", "deleted":
+	}
+}
+`,
+	})
+
+	oldEnums := *flagEnums
+	defer func() {
+		*flagEnums = oldEnums
+	}()
+
+	*flagEnums = true
+	_, out := runCapture(t, tempDir)
+	for _, want := range []string{
+		`user.go:5:30:User.Status is compared with 2 values in 2 place(s) (active, deleted), declare an enum type: type Status string; const (StatusActive Status = "active"; StatusDeleted Status = "deleted")`,
+		"\tother site(s): " + filepath.Join(tempDir, "user.go") + ":7:2",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("-enums output should contain %q, got:\n%s", want, out)
+		}
+	}
+}
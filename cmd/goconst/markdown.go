@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jgautheron/goconst"
+)
+
+// printMarkdown writes a compact summary meant to be posted as a pull-request
+// comment: a table of the most duplicated literals, one collapsible section
+// per literal listing its occurrences, and a footer with totals.
+// The output stays within maxSize bytes (0 means unlimited) by dropping
+// sections, which is reported with an explicit "more not shown" line.
+func printMarkdown(out io.Writer, issues []goconst.Issue, top, maxSize int) error {
+	groups := groupIssues(issues)
+
+	// Most duplicated first, keeping the issue order for ties.
+	ranked := append([]issueGroup(nil), groups...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Count > ranked[j].Count
+	})
+
+	files := map[string]bool{}
+	for _, group := range groups {
+		for _, file := range group.files() {
+			files[file] = true
+		}
+	}
+
+	var head strings.Builder
+	fmt.Fprintf(&head, "### goconst: %d duplicated value(s)\n\n", len(groups))
+	if len(groups) > 0 {
+		head.WriteString("| Literal | Category | Occurrences | Packages | Files | Constant |\n")
+		head.WriteString("| --- | --- | ---: | ---: | ---: | --- |\n")
+		for i, group := range ranked {
+			if top > 0 && i >= top {
+				break
+			}
+			fmt.Fprintf(&head, "| %s | %s | %d | %d | %d | %s |\n",
+				markdownCode(fmt.Sprintf("%q", group.Str)),
+				group.Category,
+				group.Count,
+				len(group.packages()),
+				len(group.files()),
+				markdownCode(strings.Join(group.Constants, ", ")),
+			)
+		}
+		head.WriteString("\n")
+	}
+
+	footer := fmt.Sprintf("---\n%d issue(s), %d duplicated value(s) in %d file(s).\n", len(issues), len(groups), len(files))
+
+	var sections []string
+	for _, group := range ranked {
+		sections = append(sections, markdownSection(group))
+	}
+
+	var body strings.Builder
+	body.WriteString(head.String())
+	shown := 0
+	for _, section := range sections {
+		if maxSize > 0 {
+			hidden := len(sections) - shown - 1
+			reserve := len(footer)
+			if hidden > 0 {
+				reserve += len(notShownLine(hidden))
+			}
+			if body.Len()+len(section)+reserve > maxSize {
+				break
+			}
+		}
+		body.WriteString(section)
+		shown++
+	}
+	if hidden := len(sections) - shown; hidden > 0 {
+		body.WriteString(notShownLine(hidden))
+	}
+	body.WriteString(footer)
+
+	result := body.String()
+	if maxSize > 0 && len(result) > maxSize {
+		// Even the table does not fit: keep the heading and totals only.
+		result = fmt.Sprintf("### goconst: %d duplicated value(s)\n\n", len(groups)) +
+			notShownLine(len(sections)) + footer
+	}
+
+	_, err := io.WriteString(out, result)
+	return err
+}
+
+func markdownSection(group issueGroup) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<details><summary><code>%s</code>: %d occurrence(s), %s</summary>\n\n",
+		htmlEscaper.Replace(fmt.Sprintf("%q", group.Str)), group.Count, group.Rule)
+	for _, loc := range group.Locations {
+		path := relativePath(loc.Filename)
+		fmt.Fprintf(&b, "- [%s:%d:%d](%s#L%d)", path, loc.Line, loc.Column, filepath.ToSlash(path), loc.Line)
+		if loc.Label != "" {
+			fmt.Fprintf(&b, " (%s)", loc.Label)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n</details>\n\n")
+	return b.String()
+}
+
+func notShownLine(n int) string {
+	return fmt.Sprintf("_%d more not shown._\n\n", n)
+}
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// markdownCode formats s as inline code safe to use in a table cell.
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "|", "\\|")
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	return fence + " " + s + " " + fence
+}
+
+// relativePath makes absolute file names relative to the working directory,
+// so that links resolve against the repository.
+func relativePath(filename string) string {
+	if !filepath.IsAbs(filename) {
+		return filename
+	}
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		return filename
+	}
+	if rel, err := filepath.Rel(wd, filename); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return filename
+}
+
+// packages returns the distinct package directories of the group.
+func (g issueGroup) packages() []string {
+	seen := map[string]bool{}
+	var dirs []string
+	for _, file := range g.files() {
+		dir := filepath.Dir(file)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printMarkdown(&buf, testIssues(), 10, 0); err != nil {
+		t.Fatalf("printMarkdown() error = %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"### goconst: 3 duplicated value(s)",
+		"| ` \"foo\" ` | other | 3 | 1 | 2 |  |",
+		"| ` \"foo\" ` | other | 2 | 1 | 1 | ` Foo ` |",
+		"| ` \"bar\" ` | other | 2 | 1 | 1 | ` Bar ` |",
+		"<details><summary><code>\"foo\"</code>: 3 occurrence(s), repeated-string</summary>",
+		"- [b.go:1:2](b.go#L1)",
+		"- [c.go:9:7](c.go#L9) (duplicate constant)",
+		"4 issue(s), 3 duplicated value(s) in 4 file(s).",
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown output missing %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "more not shown") {
+		t.Error("unlimited output should not be truncated")
+	}
+}
+
+func TestPrintMarkdownTruncated(t *testing.T) {
+	var full bytes.Buffer
+	if err := printMarkdown(&full, testIssues(), 1, 0); err != nil {
+		t.Fatalf("printMarkdown() error = %v", err)
+	}
+	if rows := strings.Count(full.String(), "\n| `"); rows != 1 {
+		t.Errorf("got %d table rows, want 1 with -markdown-top 1", rows)
+	}
+
+	limit := full.Len() - 10
+	var buf bytes.Buffer
+	if err := printMarkdown(&buf, testIssues(), 1, limit); err != nil {
+		t.Fatalf("printMarkdown() error = %v", err)
+	}
+	out := buf.String()
+
+	if len(out) > limit {
+		t.Errorf("output is %d bytes, want at most %d", len(out), limit)
+	}
+	if !strings.Contains(out, "_1 more not shown._") {
+		t.Errorf("expected a truncation notice, got:\n%s", out)
+	}
+	if !strings.Contains(out, "4 issue(s)") {
+		t.Error("footer should be kept when truncating")
+	}
+
+	buf.Reset()
+	if err := printMarkdown(&buf, testIssues(), 10, 50); err != nil {
+		t.Fatalf("printMarkdown() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "_3 more not shown._") {
+		t.Errorf("expected every section to be dropped, got:\n%s", buf.String())
+	}
+}
+
+func TestMarkdownCode(t *testing.T) {
+	tests := map[string]string{
+		"":      "",
+		"a":     "` a `",
+		"a|b":   "` a\\|b `",
+		"a`b`c": "`` a`b`c ``",
+	}
+	for in, want := range tests {
+		if got := markdownCode(in); got != want {
+			t.Errorf("markdownCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
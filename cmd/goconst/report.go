@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/jgautheron/goconst"
+)
+
+// Rule IDs shared by the structured output formats.
+const (
+	ruleRepeatedString    = "repeated-string"
+	ruleMatchingConstant  = "matching-constant"
+	ruleDuplicateConstant = "duplicate-constant"
+)
+
+// issueRule returns the rule ID an issue is reported under.
+func issueRule(issue goconst.Issue) string {
+	switch {
+	case issue.Kind != "":
+		return issue.Kind
+	case issue.DuplicateConst != "":
+		return ruleDuplicateConstant
+	case issue.MatchingConst != "":
+		return ruleMatchingConstant
+	default:
+		return ruleRepeatedString
+	}
+}
+
+// issueMessage returns a one-line, human readable description of an issue.
+func issueMessage(issue goconst.Issue) string {
+	switch issue.Kind {
+	case goconst.KindSentinelError:
+		return fmt.Sprintf("%d occurrence(s) of error message %q found, declare a sentinel error: %s",
+			issue.OccurrencesCount, issue.Str, issue.Suggestion)
+	case goconst.KindErrorComparison:
+		return fmt.Sprintf("error message %q compared through err.Error(), use %s",
+			issue.Str, issue.Suggestion)
+	case goconst.KindConfigKey:
+		return fmt.Sprintf("configuration key %q read in %d places, declare it once: %s",
+			issue.Str, issue.OccurrencesCount, issue.Suggestion)
+	case goconst.KindUndefinedConfigKey:
+		return fmt.Sprintf("configuration key %q is read but never defined or documented", issue.Str)
+	case goconst.KindEnum:
+		return fmt.Sprintf("%s is compared with %d values in %d place(s) (%s), declare an enum type: %s",
+			issue.Subject, len(issue.Values), issue.OccurrencesCount, strings.Join(issue.Values, ", "), issue.Suggestion)
+	case goconst.KindContextKey:
+		return fmt.Sprintf("context key %q used in %d place(s) may collide with other packages, use an unexported key type: %s",
+			issue.Str, issue.OccurrencesCount, issue.Suggestion)
+	}
+
+	if issue.DuplicateConst != "" {
+		return fmt.Sprintf("constant with value %q duplicates %s declared at %s",
+			issue.Str, issue.DuplicateConst, issue.DuplicatePos)
+	}
+
+	msg := fmt.Sprintf("%d occurrence(s) of %q found", issue.OccurrencesCount, issue.Str)
+	if issue.TableTest {
+		msg += " in table-driven test cases"
+	}
+	if issue.MatchingConst != "" {
+		msg += fmt.Sprintf(", a matching constant has been found: %s", issue.MatchingConst)
+	}
+	return msg
+}
+
+// fileOccurrences returns the occurrences of an issue located in its own file.
+func fileOccurrences(issue goconst.Issue) []token.Position {
+	if len(issue.Occurrences) == 0 {
+		return []token.Position{issue.Pos}
+	}
+
+	var positions []token.Position
+	for _, occ := range issue.Occurrences {
+		if occ.Filename == issue.Pos.Filename {
+			positions = append(positions, occ.Position)
+		}
+	}
+	return positions
+}
+
+// issueGroup gathers the issues reported for the same literal within the
+// same scope, or for the same duplicated constant value.
+type issueGroup struct {
+	Str       string
+	Rule      string
+	Category  string
+	Count     int
+	Constants []string
+	Locations []groupLocation
+	Issues    []goconst.Issue
+}
+
+// groupLocation is an occurrence of a literal or a constant declaration.
+type groupLocation struct {
+	token.Position
+	// Label is the context type of a literal, or the name of a constant
+	Label string
+}
+
+// groupIssues merges the per-file issues into one group per literal and scope,
+// keeping the order of the issue list.
+func groupIssues(issues []goconst.Issue) []issueGroup {
+	var groups []issueGroup
+	index := map[string]int{}
+
+	for _, issue := range issues {
+		// Issues of the same scope share their first occurrence, duplicate
+		// constants of the same scope share the constant they duplicate.
+		key := issueRule(issue) + "\x00" + issue.Str
+		switch {
+		case issue.DuplicateConst != "":
+			key += "\x00" + issue.DuplicatePos.String()
+		case len(issue.Occurrences) > 0:
+			key += "\x00" + issue.Occurrences[0].String()
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, newIssueGroup(issue))
+		}
+
+		group := &groups[i]
+		group.Issues = append(group.Issues, issue)
+		if issue.DuplicateConst != "" {
+			group.Count++
+			group.Locations = append(group.Locations, groupLocation{Position: issue.Pos, Label: "duplicate constant"})
+		}
+	}
+	return groups
+}
+
+func newIssueGroup(issue goconst.Issue) issueGroup {
+	group := issueGroup{
+		Str:      issue.Str,
+		Rule:     issueRule(issue),
+		Category: issue.Category,
+	}
+
+	if issue.DuplicateConst != "" {
+		group.Count = 1
+		group.Constants = []string{issue.DuplicateConst}
+		group.Locations = []groupLocation{{Position: issue.DuplicatePos, Label: issue.DuplicateConst}}
+		return group
+	}
+
+	group.Count = issue.OccurrencesCount
+	if issue.MatchingConst != "" {
+		group.Constants = []string{issue.MatchingConst}
+	}
+	if len(issue.Occurrences) == 0 {
+		group.Locations = []groupLocation{{Position: issue.Pos}}
+	}
+	for _, occ := range issue.Occurrences {
+		group.Locations = append(group.Locations, groupLocation{Position: occ.Position, Label: occ.Context.String()})
+	}
+	return group
+}
+
+// files returns the distinct files of the group in lexical order.
+func (g issueGroup) files() []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, loc := range g.Locations {
+		if !seen[loc.Filename] {
+			seen[loc.Filename] = true
+			files = append(files, loc.Filename)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// sortIssues orders the issues for reporting. Issues are sorted by string
+// and position by default, "score" puts the highest scores first.
+func sortIssues(issues []goconst.Issue, order string) {
+	if order == "score" {
+		sort.SliceStable(issues, func(i, j int) bool {
+			return issues[i].Score > issues[j].Score
+		})
+	}
+}
+
+// failingIssues reports whether any issue reaches the given score.
+func failingIssues(issues []goconst.Issue, minScore float64) bool {
+	for _, issue := range issues {
+		if issue.Score >= minScore {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a result map in lexical order so that
+// the structured formats are stable between runs.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lessPosition(a, b token.Position) bool {
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+	"path/filepath"
+
+	"github.com/jgautheron/goconst"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolURI      = "https://github.com/jgautheron/goconst"
+)
+
+type sarifReport struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string           `json:"ruleId"`
+	RuleIndex        int              `json:"ruleIndex"`
+	Level            string           `json:"level"`
+	Message          sarifMessage     `json:"message"`
+	Locations        []sarifLocation  `json:"locations"`
+	RelatedLocations []sarifLocation  `json:"relatedLocations,omitempty"`
+	Properties       *sarifProperties `json:"properties,omitempty"`
+}
+
+// sarifProperties holds the goconst specific data of a result.
+type sarifProperties struct {
+	Category string `json:"category,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               int                   `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+var sarifRules = []sarifRule{
+	{ID: ruleRepeatedString, ShortDescription: sarifMessage{Text: "Repeated string that could be replaced by a constant"}},
+	{ID: ruleMatchingConstant, ShortDescription: sarifMessage{Text: "Repeated string matching an existing constant"}},
+	{ID: ruleDuplicateConstant, ShortDescription: sarifMessage{Text: "Constants sharing the same value"}},
+	{ID: goconst.KindSentinelError, ShortDescription: sarifMessage{Text: "Repeated error message that could be a sentinel error"}},
+	{ID: goconst.KindErrorComparison, ShortDescription: sarifMessage{Text: "Error message compared through err.Error()"}},
+	{ID: goconst.KindConfigKey, ShortDescription: sarifMessage{Text: "Configuration key read in several places"}},
+	{ID: goconst.KindUndefinedConfigKey, ShortDescription: sarifMessage{Text: "Configuration key read but never defined or documented"}},
+	{ID: goconst.KindContextKey, ShortDescription: sarifMessage{Text: "Literal used as a context value key"}},
+	{ID: goconst.KindEnum, ShortDescription: sarifMessage{Text: "Value compared with a set of literals that could be an enum type"}},
+}
+
+// printSARIF writes the issues as a SARIF 2.1.0 log with one result per
+// duplicated literal or duplicated constant value, located at its first
+// occurrence, with the other occurrences as related locations.
+func printSARIF(out io.Writer, issues []goconst.Issue) error {
+	groups := groupIssues(issues)
+	results := make([]sarifResult, 0, len(groups))
+
+	for _, group := range groups {
+		first := group.Locations[0]
+		result := sarifResult{
+			RuleID:    group.Rule,
+			RuleIndex: sarifRuleIndex(group.Rule),
+			Level:     "warning",
+			Message:   sarifMessage{Text: issueMessage(group.Issues[0])},
+			Locations: []sarifLocation{newSARIFLocation(0, first.Position, "")},
+		}
+		if group.Category != "" {
+			result.Properties = &sarifProperties{Category: group.Category}
+		}
+		for i, loc := range group.Locations[1:] {
+			label := "other occurrence"
+			if group.Rule == ruleDuplicateConstant {
+				label = loc.Label
+			}
+			result.RelatedLocations = append(result.RelatedLocations,
+				newSARIFLocation(i+1, loc.Position, label))
+		}
+		results = append(results, result)
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifReport{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "goconst",
+				InformationURI: toolURI,
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	})
+}
+
+func sarifRuleIndex(id string) int {
+	for i, rule := range sarifRules {
+		if rule.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func newSARIFLocation(id int, pos token.Position, message string) sarifLocation {
+	loc := sarifLocation{
+		ID: id,
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: fileURI(pos.Filename)},
+			Region:           sarifRegion{StartLine: pos.Line, StartColumn: pos.Column},
+		},
+	}
+	if message != "" {
+		loc.Message = &sarifMessage{Text: message}
+	}
+	return loc
+}
+
+// fileURI converts a file name to a URI reference: relative paths stay
+// relative to the analysis root, absolute ones become file URIs.
+func fileURI(filename string) string {
+	uri := filepath.ToSlash(filename)
+	if filepath.IsAbs(filename) {
+		if uri[0] != '/' {
+			uri = "/" + uri
+		}
+		return "file://" + uri
+	}
+	return uri
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestPrintSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printSARIF(&buf, testIssues()); err != nil {
+		t.Fatalf("printSARIF() error = %v", err)
+	}
+
+	var report sarifReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v\n%s", err, buf.String())
+	}
+
+	if report.Version != "2.1.0" {
+		t.Errorf("Version = %q, want 2.1.0", report.Version)
+	}
+	if len(report.Runs) != 1 {
+		t.Fatalf("got %d runs, want 1", len(report.Runs))
+	}
+
+	results := report.Runs[0].Results
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+
+	wantRules := []string{ruleRepeatedString, ruleMatchingConstant, ruleDuplicateConstant}
+	for i, want := range wantRules {
+		if results[i].RuleID != want {
+			t.Errorf("results[%d].RuleID = %q, want %q", i, results[i].RuleID, want)
+		}
+		if rules := report.Runs[0].Tool.Driver.Rules; rules[results[i].RuleIndex].ID != want {
+			t.Errorf("results[%d].RuleIndex points to %q, want %q", i, rules[results[i].RuleIndex].ID, want)
+		}
+	}
+
+	repeated := results[0]
+	if repeated.Properties == nil || repeated.Properties.Category != "other" {
+		t.Errorf("Properties = %+v, want the literal category", repeated.Properties)
+	}
+	if got := repeated.Locations[0].PhysicalLocation; got.ArtifactLocation.URI != "a.go" || got.Region.StartLine != 3 {
+		t.Errorf("primary location = %+v, want first occurrence a.go:3", got)
+	}
+	if len(repeated.RelatedLocations) != 2 {
+		t.Fatalf("got %d related locations, want 2", len(repeated.RelatedLocations))
+	}
+	if got := repeated.RelatedLocations[1].PhysicalLocation.ArtifactLocation.URI; got != "b.go" {
+		t.Errorf("last related location = %q, want b.go", got)
+	}
+
+	// Test-scope occurrences are reported separately, with their own count.
+	if got := results[1].Message.Text; got != `2 occurrence(s) of "foo" found, a matching constant has been found: Foo` {
+		t.Errorf("test-scope message = %q", got)
+	}
+
+	dup := results[2]
+	if got := dup.Locations[0].PhysicalLocation.Region.StartLine; got != 2 {
+		t.Errorf("duplicate constant location line = %d, want 2", got)
+	}
+	if len(dup.RelatedLocations) != 1 || dup.RelatedLocations[0].PhysicalLocation.Region.StartLine != 9 {
+		t.Errorf("duplicate constant related locations = %+v, want c.go:9", dup.RelatedLocations)
+	}
+}
+
+func TestFileURI(t *testing.T) {
+	tests := map[string]string{
+		"a.go":        "a.go",
+		"pkg/b.go":    "pkg/b.go",
+		"/abs/pkg.go": "file:///abs/pkg.go",
+	}
+	for in, want := range tests {
+		if got := fileURI(in); got != want {
+			t.Errorf("fileURI(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
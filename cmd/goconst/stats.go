@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/jgautheron/goconst"
+)
+
+// runStats summarises a run, as printed by -stats.
+type runStats struct {
+	Version            int             `json:"version"`
+	Run                jsonRun         `json:"run"`
+	DuplicatedValues   int             `json:"duplicated_values"`
+	Occurrences        int             `json:"occurrences"`
+	DuplicateConstants int             `json:"duplicate_constants"`
+	Detected           map[string]int  `json:"detected,omitempty"`
+	TopLiterals        []literalStats  `json:"top_literals"`
+	TopPackages        []locationStats `json:"top_packages"`
+	TopFiles           []locationStats `json:"top_files"`
+	OccurrenceCounts   []bucketStats   `json:"occurrence_counts"`
+	LiteralLengths     []bucketStats   `json:"literal_lengths"`
+	Contexts           []bucketStats   `json:"contexts"`
+	Categories         []bucketStats   `json:"categories"`
+}
+
+// literalStats counts the occurrences of a duplicated literal.
+type literalStats struct {
+	Str         string `json:"str"`
+	Occurrences int    `json:"occurrences"`
+	Files       int    `json:"files"`
+	Packages    int    `json:"packages"`
+}
+
+// locationStats counts the duplicated occurrences in a file or package directory.
+type locationStats struct {
+	Name        string `json:"name"`
+	Occurrences int    `json:"occurrences"`
+	Values      int    `json:"values"`
+}
+
+// bucketStats is a histogram bucket.
+type bucketStats struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// statsBucket is a histogram bucket holding the values up to max (inclusive).
+type statsBucket struct {
+	label string
+	max   int
+}
+
+var (
+	occurrenceBuckets = []statsBucket{{"1", 1}, {"2", 2}, {"3-5", 5}, {"6-10", 10}, {"11-20", 20}, {"21+", 0}}
+	lengthBuckets     = []statsBucket{{"1-5", 5}, {"6-10", 10}, {"11-20", 20}, {"21-50", 50}, {"51+", 0}}
+)
+
+// histogram counts the values per bucket. The last bucket is open-ended.
+func histogram(buckets []statsBucket, values []int) []bucketStats {
+	result := make([]bucketStats, len(buckets))
+	for i, b := range buckets {
+		result[i].Label = b.label
+	}
+	for _, v := range values {
+		i := 0
+		for i < len(buckets)-1 && v > buckets[i].max {
+			i++
+		}
+		result[i].Count++
+	}
+	return result
+}
+
+// computeStats aggregates the issues of a run. Only the top entries of the
+// literal, package and file rankings are kept.
+func computeStats(meta jsonRun, issues []goconst.Issue, top int) runStats {
+	stats := runStats{Version: jsonReportVersion, Run: meta}
+
+	literals := map[string]*literalStats{}
+	files := map[string]*locationStats{}
+	packages := map[string]*locationStats{}
+	fileValues := map[string]map[string]bool{}
+	packageValues := map[string]map[string]bool{}
+	contexts := map[goconst.Type]int{}
+	categories := map[string]string{}
+	literalFiles := map[string]map[string]bool{}
+	literalPackages := map[string]map[string]bool{}
+
+	for _, group := range groupIssues(issues) {
+		if group.Rule == ruleDuplicateConstant {
+			stats.DuplicateConstants++
+			continue
+		}
+		if kind := group.Issues[0].Kind; kind != "" {
+			if stats.Detected == nil {
+				stats.Detected = map[string]int{}
+			}
+			stats.Detected[kind]++
+			continue
+		}
+
+		lit := literals[group.Str]
+		if lit == nil {
+			lit = &literalStats{Str: group.Str}
+			literals[group.Str] = lit
+			literalFiles[group.Str] = map[string]bool{}
+			literalPackages[group.Str] = map[string]bool{}
+		}
+		lit.Occurrences += group.Count
+		stats.Occurrences += group.Count
+		categories[group.Str] = group.Category
+
+		for _, occ := range group.Issues[0].Occurrences {
+			contexts[occ.Context]++
+
+			file := occ.Filename
+			dir := filepath.Dir(file)
+			literalFiles[group.Str][file] = true
+			literalPackages[group.Str][dir] = true
+
+			countLocation(files, fileValues, file, group.Str)
+			countLocation(packages, packageValues, dir, group.Str)
+		}
+	}
+
+	var occurrenceCounts, lengths []int
+	for _, str := range sortedKeys(literals) {
+		lit := literals[str]
+		lit.Files = len(literalFiles[str])
+		lit.Packages = len(literalPackages[str])
+		stats.TopLiterals = append(stats.TopLiterals, *lit)
+		occurrenceCounts = append(occurrenceCounts, lit.Occurrences)
+		lengths = append(lengths, utf8.RuneCountInString(str))
+	}
+	stats.DuplicatedValues = len(literals)
+
+	sort.SliceStable(stats.TopLiterals, func(i, j int) bool {
+		return stats.TopLiterals[i].Occurrences > stats.TopLiterals[j].Occurrences
+	})
+	stats.TopLiterals = truncateStats(stats.TopLiterals, top)
+	stats.TopPackages = rankLocations(packages, packageValues, top)
+	stats.TopFiles = rankLocations(files, fileValues, top)
+	stats.OccurrenceCounts = histogram(occurrenceBuckets, occurrenceCounts)
+	stats.LiteralLengths = histogram(lengthBuckets, lengths)
+
+	for typ := goconst.Assignment; typ <= goconst.CompositeLit; typ++ {
+		stats.Contexts = append(stats.Contexts, bucketStats{Label: typ.String(), Count: contexts[typ]})
+	}
+
+	perCategory := map[string]int{}
+	for _, category := range categories {
+		perCategory[category]++
+	}
+	for _, category := range sortedKeys(perCategory) {
+		stats.Categories = append(stats.Categories, bucketStats{Label: category, Count: perCategory[category]})
+	}
+
+	return stats
+}
+
+// countLocation records a duplicated occurrence of str in the given location.
+func countLocation(locations map[string]*locationStats, values map[string]map[string]bool, name, str string) {
+	if locations[name] == nil {
+		locations[name] = &locationStats{Name: name}
+		values[name] = map[string]bool{}
+	}
+	locations[name].Occurrences++
+	values[name][str] = true
+}
+
+// rankLocations sorts the locations by number of duplicated occurrences.
+func rankLocations(locations map[string]*locationStats, values map[string]map[string]bool, top int) []locationStats {
+	ranked := make([]locationStats, 0, len(locations))
+	for _, name := range sortedKeys(locations) {
+		loc := *locations[name]
+		loc.Values = len(values[name])
+		ranked = append(ranked, loc)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Occurrences > ranked[j].Occurrences
+	})
+	return truncateStats(ranked, top)
+}
+
+// truncateStats keeps the first top entries, all of them when top is not positive.
+func truncateStats[T any](entries []T, top int) []T {
+	if top > 0 && len(entries) > top {
+		return entries[:top]
+	}
+	return entries
+}
+
+// printStats writes the statistics of a run as text or JSON.
+func printStats(out io.Writer, stats runStats, output string) error {
+	switch output {
+	case "json":
+		return json.NewEncoder(out).Encode(stats)
+	case "text":
+	default:
+		return fmt.Errorf("unsupported output format for -stats: %s", output)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Files analyzed:\t%d\n", stats.Run.Files)
+	fmt.Fprintf(w, "Literals scanned:\t%d\n", stats.Run.Literals)
+	fmt.Fprintf(w, "Duplicated values:\t%d (%d occurrences)\n", stats.DuplicatedValues, stats.Occurrences)
+	fmt.Fprintf(w, "Duplicate constants:\t%d\n", stats.DuplicateConstants)
+	for _, kind := range sortedKeys(stats.Detected) {
+		fmt.Fprintf(w, "Findings of %s:\t%d\n", kind, stats.Detected[kind])
+	}
+
+	fmt.Fprintf(w, "\nTop literals by occurrence:\n")
+	for _, lit := range stats.TopLiterals {
+		fmt.Fprintf(w, "  %d\t%q\t%d file(s), %d package(s)\n", lit.Occurrences, lit.Str, lit.Files, lit.Packages)
+	}
+
+	fmt.Fprintf(w, "\nPackages with the most duplication:\n")
+	for _, pkg := range stats.TopPackages {
+		fmt.Fprintf(w, "  %d\t%s\t%d value(s)\n", pkg.Occurrences, pkg.Name, pkg.Values)
+	}
+
+	fmt.Fprintf(w, "\nFiles with the most duplication:\n")
+	for _, file := range stats.TopFiles {
+		fmt.Fprintf(w, "  %d\t%s\t%d value(s)\n", file.Occurrences, file.Name, file.Values)
+	}
+
+	for _, section := range []struct {
+		title   string
+		buckets []bucketStats
+	}{
+		{"Values by occurrence count", stats.OccurrenceCounts},
+		{"Values by literal length", stats.LiteralLengths},
+		{"Occurrences by context", stats.Contexts},
+		{"Values by category", stats.Categories},
+	} {
+		fmt.Fprintf(w, "\n%s:\n", section.title)
+		for _, b := range section.buckets {
+			fmt.Fprintf(w, "  %s\t%d\n", b.Label, b.Count)
+		}
+	}
+
+	return w.Flush()
+}
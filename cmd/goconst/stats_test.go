@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	stats := computeStats(jsonRun{Files: 4, Literals: 20}, testIssues(), 10)
+
+	if stats.DuplicatedValues != 1 || stats.Occurrences != 5 || stats.DuplicateConstants != 1 {
+		t.Errorf("counts = %d values, %d occurrences, %d duplicate constants, want 1, 5, 1",
+			stats.DuplicatedValues, stats.Occurrences, stats.DuplicateConstants)
+	}
+
+	want := literalStats{Str: "foo", Occurrences: 5, Files: 3, Packages: 1}
+	if len(stats.TopLiterals) != 1 || stats.TopLiterals[0] != want {
+		t.Errorf("TopLiterals = %+v, want [%+v]", stats.TopLiterals, want)
+	}
+
+	if len(stats.TopFiles) != 3 || stats.TopFiles[0].Name != "a.go" || stats.TopFiles[0].Occurrences != 2 {
+		t.Errorf("TopFiles = %+v, want a.go first with 2 occurrences", stats.TopFiles)
+	}
+	// Ties keep the name order.
+	if stats.TopFiles[1].Name != "a_test.go" {
+		t.Errorf("TopFiles[1] = %+v, want a_test.go", stats.TopFiles[1])
+	}
+
+	if got := stats.OccurrenceCounts[2]; got.Label != "3-5" || got.Count != 1 {
+		t.Errorf("OccurrenceCounts[2] = %+v, want 3-5: 1", got)
+	}
+	if got := stats.LiteralLengths[0]; got.Label != "1-5" || got.Count != 1 {
+		t.Errorf("LiteralLengths[0] = %+v, want 1-5: 1", got)
+	}
+	if got := stats.Contexts[0]; got.Label != "assignment" || got.Count != 5 {
+		t.Errorf("Contexts[0] = %+v, want assignment: 5", got)
+	}
+
+	if len(stats.Categories) != 1 || stats.Categories[0] != (bucketStats{Label: "other", Count: 1}) {
+		t.Errorf("Categories = %+v, want other: 1", stats.Categories)
+	}
+
+	if top := computeStats(jsonRun{}, testIssues(), 1); len(top.TopFiles) != 1 {
+		t.Errorf("TopFiles should be limited to 1 entry, got %d", len(top.TopFiles))
+	}
+
+	// Detector findings are counted apart from the duplicated values
+	detected := computeStats(jsonRun{}, append(testIssues(), testDetectorIssues()...), 10)
+	if detected.DuplicatedValues != 1 || detected.Occurrences != 5 || len(detected.TopLiterals) != 1 || len(detected.Categories) != 1 {
+		t.Errorf("detector findings should not count as duplicated values, got %+v", detected)
+	}
+	wantDetected := map[string]int{"sentinel-error": 1, "error-comparison": 1}
+	if !reflect.DeepEqual(detected.Detected, wantDetected) {
+		t.Errorf("Detected = %v, want %v", detected.Detected, wantDetected)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	got := histogram(occurrenceBuckets, []int{1, 2, 2, 4, 10, 11, 500})
+	want := []int{1, 2, 1, 1, 1, 1}
+	for i, b := range got {
+		if b.Count != want[i] {
+			t.Errorf("bucket %s = %d, want %d", b.Label, b.Count, want[i])
+		}
+	}
+}
+
+func TestPrintStats(t *testing.T) {
+	stats := computeStats(jsonRun{Files: 4, Literals: 20}, testIssues(), 10)
+
+	var text bytes.Buffer
+	if err := printStats(&text, stats, "text"); err != nil {
+		t.Fatalf("printStats() error = %v", err)
+	}
+	for _, want := range []string{"Files analyzed:", "Literals scanned:     20", `"foo"`, "Occurrences by context:"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, text.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := printStats(&buf, stats, "json"); err != nil {
+		t.Fatalf("printStats() error = %v", err)
+	}
+	var decoded runStats
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if decoded.Version != jsonReportVersion || decoded.Run.Literals != 20 || decoded.DuplicatedValues != 1 {
+		t.Errorf("decoded stats = %+v", decoded)
+	}
+
+	if err := printStats(&buf, stats, "sarif"); err == nil {
+		t.Error("printStats() should reject formats other than text and json")
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/jgautheron/goconst"
+)
+
+// templateIssue is the value a -format template is evaluated against.
+// It embeds the issue, so {{.Str}}, {{.OccurrencesCount}}, {{.Pos}},
+// {{.Occurrences}}, {{.MatchingConst}} and {{.DuplicateConst}} are available.
+type templateIssue struct {
+	goconst.Issue
+	// Rule is the rule ID the issue is reported under
+	Rule string
+	// Message is the one-line description used by the other formats
+	Message string
+	// Context is the context type of the reported occurrence,
+	// empty for duplicate constants
+	Context string
+}
+
+var templateFuncs = template.FuncMap{
+	"quote": strconv.Quote,
+	"join":  strings.Join,
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// loadTemplate parses the template given with -format or -format-file.
+// It returns nil when neither flag is set.
+func loadTemplate() (*template.Template, error) {
+	text := *flagFormat
+	if *flagFormatFile != "" {
+		if text != "" {
+			return nil, fmt.Errorf("-format and -format-file are mutually exclusive")
+		}
+		b, err := os.ReadFile(*flagFormatFile)
+		if err != nil {
+			return nil, err
+		}
+		text = string(b)
+	}
+	if text == "" {
+		return nil, nil
+	}
+	return template.New("format").Funcs(templateFuncs).Parse(text)
+}
+
+// printTemplate evaluates the template once per issue. A newline is
+// appended after each evaluation unless the output already ends with one.
+func printTemplate(out io.Writer, tmpl *template.Template, issues []goconst.Issue) error {
+	var buf strings.Builder
+	for _, issue := range issues {
+		data := templateIssue{
+			Issue:   issue,
+			Rule:    issueRule(issue),
+			Message: issueMessage(issue),
+		}
+		if issue.DuplicateConst == "" {
+			data.Context = issueContext(issue).String()
+		}
+
+		buf.Reset()
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return err
+		}
+		if !strings.HasSuffix(buf.String(), "\n") {
+			buf.WriteByte('\n')
+		}
+		if _, err := io.WriteString(out, buf.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issueContext returns the context type of the occurrence an issue is reported at.
+func issueContext(issue goconst.Issue) goconst.Type {
+	for _, occ := range issue.Occurrences {
+		if occ.Position == issue.Pos {
+			return occ.Context
+		}
+	}
+	return goconst.Assignment
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+)
+
+func TestPrintTemplate(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "quickfix",
+			format: `{{.Pos.Filename}}:{{.Pos.Line}}:{{.Pos.Column}}: {{.Message}}`,
+			want: `a.go:3:2: 3 occurrence(s) of "foo" found
+b.go:1:2: 3 occurrence(s) of "foo" found
+a_test.go:4:2: 2 occurrence(s) of "foo" found, a matching constant has been found: Foo
+c.go:9:7: constant with value "bar" duplicates Bar declared at c.go:2:7
+`,
+		},
+		{
+			name:   "fields and functions",
+			format: "{{quote .Str}},{{.OccurrencesCount}},{{len .Occurrences}},{{.Rule}},{{.Context}},{{.MatchingConst}}{{.DuplicateConst}}\n",
+			want: `"foo",3,3,repeated-string,assignment,
+"foo",3,3,repeated-string,assignment,
+"foo",2,2,matching-constant,assignment,Foo
+"bar",0,0,duplicate-constant,,Bar
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("format").Funcs(templateFuncs).Parse(tt.format))
+
+			var buf bytes.Buffer
+			if err := printTemplate(&buf, tmpl, testIssues()); err != nil {
+				t.Fatalf("printTemplate() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("printTemplate() =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	oldFormat, oldFormatFile := *flagFormat, *flagFormatFile
+	defer func() {
+		*flagFormat, *flagFormatFile = oldFormat, oldFormatFile
+	}()
+
+	*flagFormat, *flagFormatFile = "", ""
+	if tmpl, err := loadTemplate(); err != nil || tmpl != nil {
+		t.Errorf("loadTemplate() = %v, %v, want nil, nil", tmpl, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "format.tmpl")
+	if err := os.WriteFile(file, []byte("{{.Str}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	*flagFormatFile = file
+	if tmpl, err := loadTemplate(); err != nil || tmpl == nil {
+		t.Errorf("loadTemplate() = %v, %v, want a template", tmpl, err)
+	}
+
+	*flagFormat = "{{.Str}}"
+	if _, err := loadTemplate(); err == nil {
+		t.Error("expected an error when both -format and -format-file are set")
+	}
+
+	*flagFormat, *flagFormatFile = "{{.Str", ""
+	if _, err := loadTemplate(); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
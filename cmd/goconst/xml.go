@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/jgautheron/goconst"
+)
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// printCheckstyle writes the issues as a checkstyle report, with one error
+// per occurrence grouped under the file it appears in.
+func printCheckstyle(out io.Writer, issues []goconst.Issue) error {
+	byFile := map[string][]checkstyleError{}
+	for _, issue := range issues {
+		msg := issueMessage(issue)
+		source := "goconst." + issueRule(issue)
+		for _, pos := range fileOccurrences(issue) {
+			byFile[pos.Filename] = append(byFile[pos.Filename], checkstyleError{
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Severity: "warning",
+				Message:  msg,
+				Source:   source,
+			})
+		}
+	}
+
+	report := checkstyleReport{Version: "4.3"}
+	for _, name := range sortedKeys(byFile) {
+		errs := byFile[name]
+		sort.SliceStable(errs, func(i, j int) bool {
+			if errs[i].Line != errs[j].Line {
+				return errs[i].Line < errs[j].Line
+			}
+			return errs[i].Column < errs[j].Column
+		})
+		report.Files = append(report.Files, checkstyleFile{Name: name, Errors: errs})
+	}
+
+	return writeXML(out, report)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// printJUnit writes the issues as a JUnit report. Every duplicated string
+// (or duplicated constant value) becomes a failing test case named after the
+// literal, one per rule and scope, listing each reported location in the
+// failure body. The issues of each detector kind get a suite of their own.
+func printJUnit(out io.Writer, issues []goconst.Issue) error {
+	suites := []junitTestSuite{
+		{Name: "goconst." + ruleRepeatedString},
+		{Name: "goconst." + ruleDuplicateConstant},
+	}
+
+	kinds := map[string]int{}
+	for _, group := range groupIssues(issues) {
+		i := 0
+		switch kind := group.Issues[0].Kind; {
+		case kind != "":
+			var ok bool
+			if i, ok = kinds[kind]; !ok {
+				i = len(suites)
+				kinds[kind] = i
+				suites = append(suites, junitTestSuite{Name: "goconst." + kind})
+			}
+		case group.Rule == ruleDuplicateConstant:
+			i = 1
+		}
+
+		testCase := junitTestCase{
+			Name:      group.Str,
+			ClassName: suites[i].Name,
+			Failure: junitFailure{
+				Message: issueMessage(group.Issues[0]),
+				Type:    group.Rule,
+			},
+		}
+		for _, issue := range group.Issues {
+			testCase.Failure.Body += fmt.Sprintf("%s: %s\n", issue.Pos, issueMessage(issue))
+		}
+		suites[i].Cases = append(suites[i].Cases, testCase)
+	}
+
+	report := junitTestSuites{Name: "goconst"}
+	for _, suite := range suites {
+		suite.Tests = len(suite.Cases)
+		suite.Failures = len(suite.Cases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+
+	return writeXML(out, report)
+}
+
+func writeXML(out io.Writer, v interface{}) error {
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
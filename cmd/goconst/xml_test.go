@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/jgautheron/goconst"
+)
+
+func testIssues() []goconst.Issue {
+	pos := func(file string, line int) goconst.ExtendedPos {
+		return goconst.ExtendedPos{Position: token.Position{Filename: file, Line: line, Column: 2}}
+	}
+	prod := []goconst.ExtendedPos{pos("a.go", 3), pos("a.go", 5), pos("b.go", 1)}
+	test := []goconst.ExtendedPos{pos("a_test.go", 4), pos("a_test.go", 8)}
+
+	return []goconst.Issue{
+		{Pos: prod[0].Position, Str: "foo", OccurrencesCount: 3, Occurrences: prod, Category: goconst.CategoryOther},
+		{Pos: prod[2].Position, Str: "foo", OccurrencesCount: 3, Occurrences: prod, Category: goconst.CategoryOther},
+		{Pos: test[0].Position, Str: "foo", OccurrencesCount: 2, Occurrences: test, MatchingConst: "Foo", Category: goconst.CategoryOther},
+		{
+			Pos:            token.Position{Filename: "c.go", Line: 9, Column: 7},
+			Str:            "bar",
+			Category:       goconst.CategoryOther,
+			DuplicateConst: "Bar",
+			DuplicatePos:   token.Position{Filename: "c.go", Line: 2, Column: 7},
+		},
+	}
+}
+
+// testDetectorIssues returns the issues of two detectors for the same literal.
+func testDetectorIssues() []goconst.Issue {
+	sentinel := []goconst.ExtendedPos{
+		{Position: token.Position{Filename: "e.go", Line: 3, Column: 9}},
+		{Position: token.Position{Filename: "e.go", Line: 6, Column: 9}},
+	}
+	return []goconst.Issue{
+		{Kind: goconst.KindSentinelError, Pos: sentinel[0].Position, Str: "foo", OccurrencesCount: 2, Occurrences: sentinel},
+		{Kind: goconst.KindErrorComparison, Pos: token.Position{Filename: "e.go", Line: 9, Column: 20}, Str: "foo"},
+	}
+}
+
+func TestPrintCheckstyle(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printCheckstyle(&buf, testIssues()); err != nil {
+		t.Fatalf("printCheckstyle() error = %v", err)
+	}
+
+	var report checkstyleReport
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid checkstyle XML: %v\n%s", err, buf.String())
+	}
+
+	want := map[string]int{"a.go": 2, "a_test.go": 2, "b.go": 1, "c.go": 1}
+	if len(report.Files) != len(want) {
+		t.Fatalf("got %d files, want %d", len(report.Files), len(want))
+	}
+	for _, f := range report.Files {
+		if len(f.Errors) != want[f.Name] {
+			t.Errorf("%s: got %d errors, want %d", f.Name, len(f.Errors), want[f.Name])
+		}
+	}
+
+	testFile := report.Files[1]
+	if got := testFile.Errors[0].Message; got != `2 occurrence(s) of "foo" found, a matching constant has been found: Foo` {
+		t.Errorf("test-scope message = %q", got)
+	}
+	if got := testFile.Errors[0].Source; got != "goconst."+ruleMatchingConstant {
+		t.Errorf("test-scope source = %q", got)
+	}
+}
+
+func TestPrintJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printJUnit(&buf, append(testIssues(), testDetectorIssues()...)); err != nil {
+		t.Fatalf("printJUnit() error = %v", err)
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, buf.String())
+	}
+
+	if report.Tests != 5 || report.Failures != 5 || len(report.Suites) != 4 {
+		t.Fatalf("got tests=%d failures=%d suites=%d, want 5, 5 and 4", report.Tests, report.Failures, len(report.Suites))
+	}
+
+	// Each scope and rule of a literal is a test case of its own
+	repeated := report.Suites[0]
+	if len(repeated.Cases) != 2 || repeated.Cases[0].Name != "foo" || repeated.Cases[1].Name != "foo" {
+		t.Fatalf("repeated string suite = %+v, want two test cases named foo", repeated.Cases)
+	}
+	if got := repeated.Cases[0]; got.Failure.Type != ruleRepeatedString || !strings.Contains(got.Failure.Body, "b.go:1:2: 3 occurrence(s)") {
+		t.Errorf("production test case = %+v, want both production files", got)
+	}
+	if got := repeated.Cases[1]; got.Failure.Type != ruleMatchingConstant || !strings.Contains(got.Failure.Body, "a_test.go:4:2: 2 occurrence(s)") {
+		t.Errorf("test-scope test case = %+v, want the test-scope count", got)
+	}
+
+	dups := report.Suites[1]
+	if len(dups.Cases) != 1 || dups.Cases[0].Name != "bar" || dups.Cases[0].Failure.Type != ruleDuplicateConstant {
+		t.Errorf("duplicate constant suite = %+v", dups.Cases)
+	}
+
+	for i, kind := range []string{goconst.KindSentinelError, goconst.KindErrorComparison} {
+		suite := report.Suites[2+i]
+		if suite.Name != "goconst."+kind || len(suite.Cases) != 1 || suite.Cases[0].Failure.Type != kind {
+			t.Errorf("%s suite = %+v", kind, suite)
+		}
+	}
+}
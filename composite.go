@@ -0,0 +1,153 @@
+package goconst
+
+import (
+	"fmt"
+	"go/types"
+	"path"
+	"strings"
+)
+
+// compositeRule selects the elements of the composite literals whose type
+// matches typ, restricted to the given struct fields when fields is not
+// empty. Both are path.Match patterns.
+type compositeRule struct {
+	typ    string
+	fields []string
+}
+
+// parseCompositeRule parses a rule written "Type" or "Type{Field,...}",
+// e.g. "cobra.Command{Use,Short,Long}".
+func parseCompositeRule(rule string) (compositeRule, error) {
+	rule = strings.TrimSpace(rule)
+	typ, fields, hasFields := strings.Cut(rule, "{")
+	r := compositeRule{typ: strings.TrimSpace(typ)}
+	if hasFields {
+		var ok bool
+		if fields, ok = strings.CutSuffix(fields, "}"); !ok || strings.ContainsAny(fields, "{}") {
+			return r, fmt.Errorf("invalid composite rule %q: unbalanced braces", rule)
+		}
+		for _, field := range strings.Split(fields, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				r.fields = append(r.fields, field)
+			}
+		}
+	}
+	if r.typ == "" {
+		return r, fmt.Errorf("invalid composite rule %q: missing type", rule)
+	}
+	for _, pattern := range append([]string{r.typ}, r.fields...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return r, fmt.Errorf("invalid composite rule %q: %w", rule, err)
+		}
+	}
+	return r, nil
+}
+
+// matches reports whether the rule applies to a field of a composite
+// literal whose type is known by typeNames. field is empty for elements
+// that are not keyed by a field name.
+func (r compositeRule) matches(typeNames []string, field string) bool {
+	if !matchAny(r.typ, typeNames) {
+		return false
+	}
+	if len(r.fields) == 0 {
+		return true
+	}
+	for _, pattern := range r.fields {
+		if ok, _ := path.Match(pattern, field); ok && field != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(pattern string, names []string) bool {
+	for _, name := range names {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCompositeRules configures which literals found in composite literals
+// are ignored, or reported even when they would be ignored otherwise. Rules
+// are written "Type" for every element of the composite literals of a type,
+// or "Type{Field,...}" for some struct fields only, such as
+// "cobra.Command{Use,Short,Long}" or "Config{Endpoint}". Types are matched
+// as written and, when type information is available, by package path
+// (e.g. "github.com/spf13/cobra.Command"), which also covers the elements
+// of a slice whose type is elided. Types and fields may contain path.Match
+// wildcards.
+//
+// Include rules take precedence over the ignore rules and over the
+// exclusion of the CompositeLit context.
+func (p *Parser) SetCompositeRules(ignore, include []string) error {
+	var err error
+	if p.ignoreComposites, err = parseCompositeRules(ignore); err != nil {
+		return err
+	}
+	p.includeComposites, err = parseCompositeRules(include)
+	return err
+}
+
+func parseCompositeRules(rules []string) ([]compositeRule, error) {
+	var parsed []compositeRule
+	for _, rule := range rules {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		r, err := parseCompositeRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+// hasCompositeRules reports whether any composite literal rule is set.
+func (p *Parser) hasCompositeRules() bool {
+	return len(p.ignoreComposites) > 0 || len(p.includeComposites) > 0
+}
+
+// compositeElement reports whether a field of a composite literal of a type
+// known by typeNames is ignored, or included whatever the other filters.
+func (p *Parser) compositeElement(typeNames []string, field string) (ignored, included bool) {
+	for _, rule := range p.includeComposites {
+		if rule.matches(typeNames, field) {
+			return false, true
+		}
+	}
+	for _, rule := range p.ignoreComposites {
+		if rule.matches(typeNames, field) {
+			return true, false
+		}
+	}
+	return false, false
+}
+
+// namedTypeNames returns the names a named type is known by: its
+// package-qualified name, its import path qualified name and, for types of
+// the package being analyzed, its bare name. It returns nil for other types.
+func namedTypeNames(typ types.Type, packageName string) []string {
+	if ptr, ok := types.Unalias(typ).(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok {
+		return nil
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return []string{obj.Name()}
+	}
+	names := []string{obj.Pkg().Name() + "." + obj.Name()}
+	if obj.Pkg().Path() != "" {
+		names = append(names, obj.Pkg().Path()+"."+obj.Name())
+	}
+	if obj.Pkg().Name() == packageName {
+		names = append(names, obj.Name())
+	}
+	return names
+}
@@ -0,0 +1,127 @@
+package goconst
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestParseCompositeRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    compositeRule
+		wantErr bool
+	}{
+		{rule: "Config", want: compositeRule{typ: "Config"}},
+		{rule: " cobra.Command{Use, Short,Long} ", want: compositeRule{typ: "cobra.Command", fields: []string{"Use", "Short", "Long"}}},
+		{rule: "*{Name}", want: compositeRule{typ: "*", fields: []string{"Name"}}},
+		{rule: "Config{}", want: compositeRule{typ: "Config"}},
+		{rule: "Config{Endpoint", wantErr: true},
+		{rule: "Config{{Endpoint}}", wantErr: true},
+		{rule: "{Endpoint}", wantErr: true},
+		{rule: "Config[{Endpoint}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseCompositeRule(tt.rule)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCompositeRule(%q) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (got.typ != tt.want.typ || strings.Join(got.fields, ",") != strings.Join(tt.want.fields, ",")) {
+			t.Errorf("parseCompositeRule(%q) = %+v, want %+v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestRunWithConfig_CompositeRules(t *testing.T) {
+	code := `package example
+import "net/http"
+type Command struct{ Use, Short string }
+type Config struct{ Endpoint, Name string }
+var (
+	a = Command{Use: "serve", Short: "start it"}
+	b = Command{Use: "serve", Short: "start it"}
+	c = &http.Request{Method: "PATCH"}
+	d = &http.Request{Method: "PATCH"}
+	e = []Config{{Endpoint: "localhost", Name: "local"}, {Endpoint: "localhost", Name: "local"}}
+)`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	info := resolvedTypes(fset, f)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		noTypes bool
+		want    []string
+	}{
+		{
+			name: "no rules",
+			want: []string{"PATCH", "local", "localhost", "serve", "start it"},
+		},
+		{
+			name: "fields",
+			cfg:  Config{IgnoreComposites: []string{"Command{Use,Short}", "http.Request{Method}"}},
+			want: []string{"local", "localhost"},
+		},
+		{
+			name: "import path and elided type",
+			cfg:  Config{IgnoreComposites: []string{"net/http.Request", "Config{Name}"}},
+			want: []string{"localhost", "serve", "start it"},
+		},
+		{
+			name:    "elided type needs type information",
+			cfg:     Config{IgnoreComposites: []string{"Config"}},
+			noTypes: true,
+			want:    []string{"PATCH", "local", "localhost", "serve", "start it"},
+		},
+		{
+			name: "include overrides ignore",
+			cfg:  Config{IgnoreComposites: []string{"*"}, IncludeComposites: []string{"Config{End*}"}},
+			want: []string{"localhost"},
+		},
+		{
+			name: "include overrides excluded context",
+			cfg: Config{
+				ExcludeTypes:      map[Type]bool{CompositeLit: true},
+				IncludeComposites: []string{"example.Command{Use}"},
+			},
+			want: []string{"serve"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.MinStringLength = 3
+			cfg.MinOccurrences = 2
+
+			typeInfo := info
+			if tt.noTypes {
+				typeInfo = nil
+			}
+			issues, err := RunWithConfig([]*ast.File{f}, fset, typeInfo, &cfg)
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.Str)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := RunWithConfig([]*ast.File{f}, fset, nil, &Config{IncludeComposites: []string{"Config{"}}); err == nil {
+		t.Error("RunWithConfig() should reject malformed composite rules")
+	}
+}
@@ -0,0 +1,301 @@
+package goconst
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sources of the configuration keys.
+const (
+	// ConfigSourceEnv is an environment variable
+	ConfigSourceEnv = "env"
+	// ConfigSourceFlag is a command-line flag
+	ConfigSourceFlag = "flag"
+	// ConfigSourceAccessor is a key of the accessors configured with
+	// Parser.SetConfigKeys, such as viper.GetString
+	ConfigSourceAccessor = "config"
+)
+
+// configKeyPrefixes prefix the names of the constants suggested for keys.
+var configKeyPrefixes = map[string]string{
+	ConfigSourceEnv:      "Env",
+	ConfigSourceFlag:     "Flag",
+	ConfigSourceAccessor: "Key",
+}
+
+// configKeyCall describes a function taking a configuration key.
+type configKeyCall struct {
+	source string
+	define bool
+	// index is the index of the key argument
+	index int
+}
+
+// configKeyCalls are the functions of the standard library taking a
+// configuration key.
+var configKeyCalls = map[string]configKeyCall{
+	"os.Getenv":    {ConfigSourceEnv, false, 0},
+	"os.LookupEnv": {ConfigSourceEnv, false, 0},
+	"os.Setenv":    {ConfigSourceEnv, true, 0},
+	"flag.Lookup":  {ConfigSourceFlag, false, 0},
+	"flag.Set":     {ConfigSourceFlag, false, 0},
+}
+
+func init() {
+	for _, prefix := range []string{"flag.", "(*flag.FlagSet)."} {
+		for _, name := range []string{"Bool", "BoolFunc", "Duration", "Float64", "Func", "Int", "Int64", "String", "Uint", "Uint64"} {
+			configKeyCalls[prefix+name] = configKeyCall{ConfigSourceFlag, true, 0}
+		}
+		for _, name := range []string{"BoolVar", "DurationVar", "Float64Var", "Int64Var", "IntVar", "StringVar", "TextVar", "Uint64Var", "UintVar", "Var"} {
+			configKeyCalls[prefix+name] = configKeyCall{ConfigSourceFlag, true, 1}
+		}
+	}
+	configKeyCalls["(*flag.FlagSet).Lookup"] = configKeyCall{ConfigSourceFlag, false, 0}
+	configKeyCalls["(*flag.FlagSet).Set"] = configKeyCall{ConfigSourceFlag, false, 0}
+}
+
+// configKeyRule is a user-configured accessor taking a configuration key.
+type configKeyRule struct {
+	pattern string
+	configKeyCall
+}
+
+// configKeyUses holds the places reading and defining the keys of a source.
+type configKeyUses struct {
+	reads       map[string][]ExtendedPos
+	definitions map[string][]ExtendedPos
+}
+
+// ConfigKey is a configuration key with the places reading and defining it.
+type ConfigKey struct {
+	Source      string           `json:"source"`
+	Key         string           `json:"key"`
+	Reads       []token.Position `json:"reads,omitempty"`
+	Definitions []token.Position `json:"definitions,omitempty"`
+	// Documented is set when the key is mentioned by one of the files
+	// passed to Parser.SetConfigKeyDocs
+	Documented bool `json:"documented"`
+}
+
+// SetConfigKeys enables the detection of configuration keys: the keys
+// passed to os.Getenv, os.LookupEnv and os.Setenv, to the flag
+// constructors and to flag.Lookup, and to the accessors matching readers
+// and definers. Accessors are named like the calls of SetIgnoreFunctions,
+// e.g. "viper.GetString", "viper.Get*" or "(*github.com/spf13/viper.Viper).Get*",
+// and take the key as first argument unless the name is followed by "#N".
+//
+// Keys read in at least MinOccurrences places are reported as
+// KindConfigKey. Keys read but never defined, nor mentioned by the
+// documentation files, are reported as KindUndefinedConfigKey, provided
+// their source has definitions or documentation files are set. These
+// literals are no longer reported as repeated strings. ConfigKeys returns
+// the inventory of the keys.
+func (p *Parser) SetConfigKeys(enabled bool, readers, definers []string) {
+	p.configKeys = enabled
+	p.configKeyRules = nil
+	if !enabled {
+		return
+	}
+
+	for _, rules := range []struct {
+		names  []string
+		define bool
+	}{{readers, false}, {definers, true}} {
+		for _, name := range rules.names {
+			name, arg, _ := strings.Cut(strings.TrimSpace(name), "#")
+			if name == "" {
+				continue
+			}
+			rule := configKeyRule{
+				pattern:       strings.ReplaceAll(name, "(*", `(\*`),
+				configKeyCall: configKeyCall{ConfigSourceAccessor, rules.define, 0},
+			}
+			if index, err := strconv.Atoi(arg); err == nil && index >= 0 {
+				rule.index = index
+			}
+			p.configKeyRules = append(p.configKeyRules, rule)
+		}
+	}
+
+	if p.configKeyUses == nil {
+		p.configKeyUses = make(map[string]*configKeyUses)
+		for source := range configKeyPrefixes {
+			p.configKeyUses[source] = &configKeyUses{
+				reads:       make(map[string][]ExtendedPos),
+				definitions: make(map[string][]ExtendedPos),
+			}
+		}
+	}
+}
+
+// SetConfigKeyDocs reads the files documenting the configuration keys, such
+// as a README or a .env.example. A key mentioned by one of them is not
+// reported as undefined.
+func (p *Parser) SetConfigKeyDocs(files []string) error {
+	p.configKeyDocs = nil
+	for _, file := range files {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("reading config key documentation: %w", err)
+		}
+		p.configKeyDocs = append(p.configKeyDocs, string(content))
+	}
+	return nil
+}
+
+// configKeyCall returns how a callee known by names takes a configuration
+// key. The standard library functions take precedence over the accessors.
+func (p *Parser) configKeyCall(names []string) (configKeyCall, bool) {
+	for _, name := range names {
+		if call, ok := configKeyCalls[name]; ok {
+			return call, true
+		}
+	}
+	for _, rule := range p.configKeyRules {
+		if matchAny(rule.pattern, names) {
+			return rule.configKeyCall, true
+		}
+	}
+	return configKeyCall{}, false
+}
+
+// addConfigKey records the key of a call taking a configuration key. It
+// returns the index of the key argument, -1 when the call takes none.
+func (v *treeVisitor) addConfigKey(call *ast.CallExpr) int {
+	kc, ok := v.p.configKeyCall(v.callNames(call.Fun))
+	if !ok || kc.index >= len(call.Args) {
+		return -1
+	}
+	lit, ok := call.Args[kc.index].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return -1
+	}
+
+	uses := v.p.configKeyUses[kc.source]
+	if kc.define {
+		v.addDetected(uses.definitions, lit, Call)
+	} else {
+		v.addDetected(uses.reads, lit, Call)
+	}
+	return kc.index
+}
+
+// configKeyIssues returns the issues of the config key detector.
+func (p *Parser) configKeyIssues() []Issue {
+	p.detectorMutex.Lock()
+	defer p.detectorMutex.Unlock()
+
+	var issues []Issue
+	for _, source := range sortedKeys(p.configKeyUses) {
+		uses := p.configKeyUses[source]
+		checkDefined := len(uses.definitions) > 0 || len(p.configKeyDocs) > 0
+		for _, key := range sortedKeys(uses.reads) {
+			reads := uses.reads[key]
+			issues = append(issues, p.scopedIssues(key, reads, p.minOccurrences, false, Issue{
+				Kind:       KindConfigKey,
+				Suggestion: fmt.Sprintf("const %s = %q", identifier(configKeyPrefixes[source], key, 0), key),
+			})...)
+			if checkDefined && len(uses.definitions[key]) == 0 && !p.documented(key) {
+				issues = append(issues, p.scopedIssues(key, reads, 1, false, Issue{
+					Kind: KindUndefinedConfigKey,
+				})...)
+			}
+		}
+	}
+	return issues
+}
+
+// ConfigKeys returns the configuration keys found by ParseTree, sorted by
+// source and key, when SetConfigKeys is enabled.
+func (p *Parser) ConfigKeys() []ConfigKey {
+	p.detectorMutex.Lock()
+	defer p.detectorMutex.Unlock()
+
+	var keys []ConfigKey
+	for _, source := range sortedKeys(p.configKeyUses) {
+		uses := p.configKeyUses[source]
+		names := make(map[string]bool)
+		for key := range uses.reads {
+			names[key] = true
+		}
+		for key := range uses.definitions {
+			names[key] = true
+		}
+		for _, key := range sortedKeys(names) {
+			keys = append(keys, ConfigKey{
+				Source:      source,
+				Key:         key,
+				Reads:       sortedPositions(uses.reads[key]),
+				Definitions: sortedPositions(uses.definitions[key]),
+				Documented:  p.documented(key),
+			})
+		}
+	}
+	return keys
+}
+
+// documented reports whether a documentation file mentions the key as a
+// whole word: "db" is not mentioned by "db.host", but "DB_HOST" is by
+// "Set DB_HOST." and "dry-run" by "--dry-run".
+func (p *Parser) documented(key string) bool {
+	for _, doc := range p.configKeyDocs {
+		for offset := 0; ; {
+			i := strings.Index(doc[offset:], key)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(key)
+			if !continuesKey(doc[:start], true) && !continuesKey(doc[end:], false) {
+				return true
+			}
+			offset = start + 1
+		}
+	}
+	return false
+}
+
+// continuesKey reports whether the text next to a key, before or after it,
+// makes it part of a longer key. Separators such as "." and "-" only do
+// when a letter or digit follows them.
+func continuesKey(text string, before bool) bool {
+	next := utf8.DecodeRuneInString
+	if before {
+		next = utf8.DecodeLastRuneInString
+	}
+	r, size := next(text)
+	switch {
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+		return true
+	case r == '.' || r == '-':
+		rest := text[size:]
+		if before {
+			rest = text[:len(text)-size]
+		}
+		r, _ = next(rest)
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	return false
+}
+
+func sortedPositions(positions []ExtendedPos) []token.Position {
+	if len(positions) == 0 {
+		return nil
+	}
+	positions = append([]ExtendedPos(nil), positions...)
+	sortPositions(positions)
+	sorted := make([]token.Position, len(positions))
+	for i, pos := range positions {
+		sorted[i] = pos.Position
+	}
+	return sorted
+}
@@ -0,0 +1,155 @@
+package goconst
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const configKeysCode = `package example
+
+import (
+	"flag"
+	"os"
+)
+
+var verbose = flag.Bool("verbose", false, "verbose output")
+
+func dsn() string {
+	return os.Getenv("DATABASE_URL")
+}
+
+func migrate() {
+	if _, ok := os.LookupEnv("DATABASE_URL"); ok {
+		_ = flag.Lookup("verbose")
+		_ = flag.Lookup("dry-run")
+	}
+	_ = os.Getenv("HTTP_PORT")
+	_ = viper.GetString("db.host")
+	_ = viper.GetString("db.host")
+	viper.SetDefault("db.port", 5432)
+	_ = viper.GetInt("db.port")
+	_ = viper.GetInt("db.user")
+}`
+
+func TestRunWithConfig_ConfigKeys(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", configKeysCode, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	docs := filepath.Join(t.TempDir(), ".env.example")
+	if err := os.WriteFile(docs, []byte("DATABASE_URL=postgres://\nHTTP_PORTS=8080\n"), 0644); err != nil {
+		t.Fatalf("Failed to write docs: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{
+			name: "disabled",
+			want: []string{"DATABASE_URL", "db.host", "db.port", "verbose"},
+		},
+		{
+			name: "builtin",
+			cfg:  Config{ConfigKeys: true},
+			want: []string{"DATABASE_URL config-key EnvDatabaseUrl", "db.host", "db.port", "dry-run undefined-config-key"},
+		},
+		{
+			name: "accessors and docs",
+			cfg: Config{
+				ConfigKeys:        true,
+				ConfigKeyReaders:  []string{"viper.Get*"},
+				ConfigKeyDefiners: []string{"viper.SetDefault"},
+				ConfigKeyDocs:     []string{docs},
+			},
+			want: []string{
+				"DATABASE_URL config-key EnvDatabaseUrl", "HTTP_PORT undefined-config-key",
+				"db.host config-key KeyDbHost", "db.host undefined-config-key", "db.user undefined-config-key",
+				"dry-run undefined-config-key",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.MinStringLength = 3
+			cfg.MinOccurrences = 2
+			issues, err := RunWithConfig([]*ast.File{f}, fset, nil, &cfg)
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				desc := issue.Str
+				if issue.Kind != "" {
+					desc += " " + issue.Kind
+				}
+				if issue.Suggestion != "" {
+					desc += " " + strings.Fields(issue.Suggestion)[1]
+				}
+				got = append(got, desc)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := RunWithConfig([]*ast.File{f}, fset, nil, &Config{ConfigKeys: true, ConfigKeyDocs: []string{docs + ".missing"}}); err == nil {
+		t.Error("RunWithConfig() should fail on a missing documentation file")
+	}
+}
+
+func TestParser_ConfigKeys(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "example.go"), []byte(configKeysCode), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	p := New(tempDir, "", "", false, false, false, false, false, 0, 0, 3, 2, map[Type]bool{})
+	p.SetConfigKeys(true, []string{"viper.GetString#0"}, nil)
+	if _, _, err := p.ParseTree(); err != nil {
+		t.Fatalf("ParseTree() error = %v", err)
+	}
+
+	var got []string
+	for _, key := range p.ConfigKeys() {
+		got = append(got, key.Source+" "+key.Key+" "+strings.Repeat("r", len(key.Reads))+strings.Repeat("d", len(key.Definitions)))
+	}
+	want := []string{
+		"config db.host rr",
+		"env DATABASE_URL rr", "env HTTP_PORT r",
+		"flag dry-run r", "flag verbose rd",
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("ConfigKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestParser_Documented(t *testing.T) {
+	p := &Parser{configKeyDocs: []string{"Set `DATABASE_URL` or db.host.\n--dry-run: print only\n"}}
+	tests := map[string]bool{
+		"DATABASE_URL": true,
+		"DATABASE":     false,
+		"db.host":      true,
+		"db":           false,
+		"dry-run":      true,
+		"run":          false,
+	}
+	for key, want := range tests {
+		if got := p.documented(key); got != want {
+			t.Errorf("documented(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
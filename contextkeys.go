@@ -0,0 +1,123 @@
+package goconst
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// contextMethods are the methods of context.Context. A type having all of
+// them is taken for a context, whatever its package.
+var contextMethods = []string{"Deadline", "Done", "Err", "Value"}
+
+// SetContextKeys enables the detection of the literals of built-in types
+// used as context value keys, in context.WithValue and in the Value method
+// of a context. Such keys collide with the keys of any other package using
+// the same value, unlike the values of an unexported key type. Every use
+// of a key is reported as KindContextKey, and is no longer reported as a
+// repeated string.
+func (p *Parser) SetContextKeys(enabled bool) {
+	p.contextKeys = enabled
+	if enabled && p.contextKeyUses == nil {
+		p.contextKeyUses = make(map[string][]ExtendedPos)
+	}
+}
+
+// addContextKey records the key of a call storing or reading a context
+// value. It returns the index of the key argument, -1 when the call is
+// neither or its key is not a literal.
+func (v *treeVisitor) addContextKey(call *ast.CallExpr) int {
+	index := -1
+	switch {
+	case len(call.Args) == 3 && containsName(v.callNames(call.Fun), "context.WithValue"):
+		index = 1
+	case len(call.Args) == 1 && v.isContextValue(call.Fun):
+		index = 0
+	default:
+		return -1
+	}
+
+	lit, ok := call.Args[index].(*ast.BasicLit)
+	if !ok {
+		return -1
+	}
+	key := lit.Value
+	if lit.Kind == token.STRING {
+		str, err := strconv.Unquote(lit.Value)
+		if err != nil || v.ignoreRegex != nil && v.ignoreRegex.MatchString(str) {
+			return -1
+		}
+		// Raw and interpreted strings of the same value are the same key
+		key = strconv.Quote(str)
+	}
+
+	key = InternString(key)
+	v.p.detectorMutex.Lock()
+	defer v.p.detectorMutex.Unlock()
+	v.p.contextKeyUses[key] = append(v.p.contextKeyUses[key], v.position(lit.Pos(), Call))
+	return index
+}
+
+// isContextValue reports whether fun is the Value method of a context.
+// Without type information, the receiver must be named like a context,
+// such as ctx or reqCtx.
+func (v *treeVisitor) isContextValue(fun ast.Expr) bool {
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Value" {
+		return false
+	}
+	if v.typeInfo != nil {
+		if typ := v.typeInfo.TypeOf(sel.X); typ != nil && typ != types.Typ[types.Invalid] {
+			return isContext(typ)
+		}
+	}
+	name := selectorPath(sel.X)
+	name = name[strings.LastIndex(name, ".")+1:]
+	return name == "ctx" || strings.HasSuffix(name, "Ctx")
+}
+
+// isContext reports whether the type has the methods of context.Context.
+func isContext(typ types.Type) bool {
+	for _, method := range contextMethods {
+		obj, _, _ := types.LookupFieldOrMethod(typ, true, nil, method)
+		if _, ok := obj.(*types.Func); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// contextKeyIssues returns the issues of the context key detector.
+func (p *Parser) contextKeyIssues() []Issue {
+	p.detectorMutex.Lock()
+	defer p.detectorMutex.Unlock()
+
+	var issues []Issue
+	for _, key := range sortedKeys(p.contextKeyUses) {
+		str := key
+		if unquoted, err := strconv.Unquote(key); err == nil {
+			str = unquoted
+		}
+		issues = append(issues, p.scopedIssues(str, p.contextKeyUses[key], 1, true, Issue{
+			Kind:       KindContextKey,
+			Suggestion: fmt.Sprintf("type %s struct{}", contextKeyType(str)),
+		})...)
+	}
+	return issues
+}
+
+// contextKeyType derives the name of an unexported key type from a key,
+// e.g. userIdKey for "user-id".
+func contextKeyType(key string) string {
+	name := identifier("", key, 4)
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		return "ctxKey" + name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes) + "Key"
+}
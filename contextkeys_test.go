@@ -0,0 +1,106 @@
+package goconst
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRunWithConfig_ContextKeys(t *testing.T) {
+	code := `package example
+
+import (
+	"context"
+	"net/http"
+)
+
+type values map[string]string
+
+func (v values) Value(key string) string { return v[key] }
+
+func store(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, "user", 1)
+	return context.WithValue(ctx, ` + "`user`" + `, 42)
+}
+
+func load(r *http.Request, reqCtx context.Context, v values) {
+	_ = r.Context().Value("user")
+	_ = reqCtx.Value(42)
+	_ = v.Value("user")
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		typeInfo *types.Info
+		want     []string
+	}{
+		{
+			name: "names",
+			want: []string{
+				"13:31 user 2 type userKey struct{}", "14:32 user 2 type userKey struct{}",
+				"19:19 42 1 type ctxKey42 struct{}", "user",
+			},
+		},
+		{
+			name:     "types",
+			typeInfo: resolvedTypes(fset, f),
+			want: []string{
+				"13:31 user 3 type userKey struct{}", "14:32 user 3 type userKey struct{}",
+				"18:24 user 3 type userKey struct{}", "19:19 42 1 type ctxKey42 struct{}",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := RunWithConfig([]*ast.File{f}, fset, tt.typeInfo, &Config{
+				MinStringLength: 3,
+				MinOccurrences:  2,
+				ContextKeys:     true,
+			})
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				desc := issue.Str
+				if issue.Kind == KindContextKey {
+					desc = fmt.Sprintf("%d:%d %s %d %s", issue.Pos.Line, issue.Pos.Column, issue.Str, issue.OccurrencesCount, issue.Suggestion)
+				}
+				got = append(got, desc)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextKeyType(t *testing.T) {
+	tests := map[string]string{
+		"user":           "userKey",
+		"request-id":     "requestIdKey",
+		"X-Trace-ID":     "xTraceIdKey",
+		"42":             "ctxKey42",
+		"":               "ctxKey",
+		"Été":            "étéKey",
+		"a.b.c.d.e.f.gh": "aBCDKey",
+	}
+	for key, want := range tests {
+		if got := contextKeyType(key); got != want {
+			t.Errorf("contextKeyType(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
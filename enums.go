@@ -0,0 +1,206 @@
+package goconst
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// enumSubject is a variable, a struct field or a named string type compared
+// with literals.
+type enumSubject struct {
+	// name describes the subject, e.g. "status" or "User.Status"
+	name string
+	// typeName is the named type of the subject, empty for plain strings
+	typeName string
+	// sites are the comparisons and switch statements, values the literals
+	// each of them compares the subject with
+	sites  []ExtendedPos
+	values map[token.Position][]string
+}
+
+// SetEnums enables the detection of the variables, struct fields and named
+// string types compared with == and != or switched on against a set of
+// literals in at least MinOccurrences places. Each is reported as KindEnum
+// with the subject, the values found, the first of them being the literal
+// of the issue, the comparison and switch sites as occurrences and a
+// suggested named string type with a const block. Subjects are told apart
+// by their declaration with type information, by their name otherwise. The
+// literals are still counted as repeated strings.
+func (p *Parser) SetEnums(enabled bool) {
+	p.enums = enabled
+	if enabled && p.enumSubjects == nil {
+		p.enumSubjects = make(map[string]*enumSubject)
+	}
+}
+
+// addEnumComparison records the comparison of a subject with a literal.
+func (v *treeVisitor) addEnumComparison(expr *ast.BinaryExpr) {
+	x, y := expr.X, expr.Y
+	if _, ok := x.(*ast.BasicLit); ok {
+		x, y = y, x
+	}
+	if value, ok := v.enumValue(y); ok {
+		v.addEnumSite(x, expr.Pos(), Binary, []string{value})
+	}
+}
+
+// addEnumSwitch records the literals of the cases of a switch statement.
+func (v *treeVisitor) addEnumSwitch(stmt *ast.SwitchStmt) {
+	var values []string
+	for _, clause := range stmt.Body.List {
+		clause, ok := clause.(*ast.CaseClause)
+		if !ok {
+			continue
+		}
+		for _, item := range clause.List {
+			if value, ok := v.enumValue(item); ok {
+				values = append(values, value)
+			}
+		}
+	}
+	if len(values) > 0 {
+		v.addEnumSite(stmt.Tag, stmt.Pos(), Case, values)
+	}
+}
+
+// enumValue returns the value of a non-empty string literal.
+func (v *treeVisitor) enumValue(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil || value == "" || v.ignoreRegex != nil && v.ignoreRegex.MatchString(value) {
+		return "", false
+	}
+	return value, true
+}
+
+func (v *treeVisitor) addEnumSite(expr ast.Expr, pos token.Pos, typ Type, values []string) {
+	key, subject := v.enumSubject(ast.Unparen(expr))
+	if key == "" {
+		return
+	}
+
+	site := v.position(pos, typ)
+	v.p.detectorMutex.Lock()
+	defer v.p.detectorMutex.Unlock()
+	if known, ok := v.p.enumSubjects[key]; ok {
+		subject = known
+	} else {
+		subject.values = make(map[token.Position][]string)
+		v.p.enumSubjects[key] = subject
+	}
+	subject.sites = append(subject.sites, site)
+	subject.values[site.Position] = append(subject.values[site.Position], values...)
+}
+
+// enumSubject identifies the subject of a comparison. With type
+// information, values of the same named string type share a subject, and
+// other subjects are fields, told apart by their struct type, or variables,
+// told apart by their declaration. Without it, fields and variables are
+// only known by their name. It returns an empty key for other expressions.
+func (v *treeVisitor) enumSubject(expr ast.Expr) (string, *enumSubject) {
+	if v.typeInfo == nil {
+		switch e := expr.(type) {
+		case *ast.SelectorExpr:
+			return "field:" + e.Sel.Name, &enumSubject{name: e.Sel.Name}
+		case *ast.Ident:
+			return "var:" + e.Name, &enumSubject{name: e.Name}
+		}
+		return "", nil
+	}
+
+	typ := v.typeInfo.TypeOf(expr)
+	if typ == nil {
+		return "", nil
+	}
+	if basic, ok := typ.Underlying().(*types.Basic); !ok || basic.Info()&types.IsString == 0 {
+		return "", nil
+	}
+	if named, ok := types.Unalias(typ).(*types.Named); ok {
+		return "type:" + receiverName(named), &enumSubject{name: named.Obj().Name(), typeName: named.Obj().Name()}
+	}
+
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		if selection, ok := v.typeInfo.Selections[e]; ok && selection.Kind() == types.FieldVal {
+			if recv := receiverName(selection.Recv()); recv != "" {
+				return "field:" + recv + "." + e.Sel.Name, &enumSubject{name: recv[strings.LastIndex(recv, ".")+1:] + "." + e.Sel.Name}
+			}
+		}
+	case *ast.Ident:
+		if obj, ok := v.typeInfo.Uses[e].(*types.Var); ok {
+			return "var:" + v.fileSet.Position(obj.Pos()).String() + ":" + e.Name, &enumSubject{name: e.Name}
+		}
+	}
+	return "", nil
+}
+
+// enumIssues returns the issues of the enum detector.
+func (p *Parser) enumIssues() []Issue {
+	p.detectorMutex.Lock()
+	defer p.detectorMutex.Unlock()
+
+	var issues []Issue
+	for _, key := range sortedKeys(p.enumSubjects) {
+		subject := p.enumSubjects[key]
+		for _, issue := range p.scopeIssues(subject.name, subject.sites, p.minOccurrences, false, Issue{Kind: KindEnum, Subject: subject.name}) {
+			seen := make(map[string]bool)
+			for _, site := range issue.Occurrences {
+				for _, value := range subject.values[site.Position] {
+					if !seen[value] {
+						seen[value] = true
+						issue.Values = append(issue.Values, value)
+					}
+				}
+			}
+			if len(issue.Values) < 2 {
+				continue
+			}
+			sort.Strings(issue.Values)
+			issue.Str = issue.Values[0]
+			issue.Suggestion = enumDeclaration(subject, issue.Values)
+			issues = append(issues, issue)
+		}
+	}
+	return p.categorized(issues)
+}
+
+// enumDeclaration suggests the declaration of an enum type and its values,
+// only the values when the subject already has a named type, e.g.
+// `type Status string; const (StatusActive Status = "active"; ...)`.
+func enumDeclaration(subject *enumSubject, values []string) string {
+	typeName := subject.typeName
+	var decl strings.Builder
+	if typeName == "" {
+		name := []rune(subject.name[strings.LastIndex(subject.name, ".")+1:])
+		name[0] = unicode.ToUpper(name[0])
+		typeName = string(name)
+		fmt.Fprintf(&decl, "type %s string; ", typeName)
+	}
+
+	// Values differing only by punctuation or case share an identifier
+	used := map[string]bool{typeName: true}
+	decl.WriteString("const (")
+	for i, value := range values {
+		if i > 0 {
+			decl.WriteString("; ")
+		}
+		base := identifier(typeName, value, 0)
+		name := base
+		for n := i + 1; used[name]; n++ {
+			name = base + strconv.Itoa(n)
+		}
+		used[name] = true
+		fmt.Fprintf(&decl, "%s %s = %q", name, typeName, value)
+	}
+	decl.WriteString(")")
+	return decl.String()
+}
@@ -0,0 +1,165 @@
+package goconst
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRunWithConfig_Enums(t *testing.T) {
+	code := `package example
+
+type Kind string
+
+type User struct {
+	Status string
+	Kind   Kind
+}
+
+type Order struct {
+	Status string
+}
+
+func activate(u *User) {
+	if u.Status == "suspended" {
+		u.Status = "active"
+	}
+}
+
+func remove(u User, o Order) {
+	switch u.Status {
+	case "active", "suspended":
+	case "deleted":
+	}
+	if o.Status != "paid" {
+		return
+	}
+}
+
+func kinds(u, v User) {
+	if u.Kind == "admin" || v.Kind == "guest" {
+		return
+	}
+	if (u.Kind) == "" {
+		return
+	}
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		typeInfo *types.Info
+		want     []string
+	}{
+		{
+			name: "names",
+			want: []string{
+				`Kind admin 2 admin,guest type Kind string; const (KindAdmin Kind = "admin"; KindGuest Kind = "guest")`,
+				`Status active 3 active,deleted,paid,suspended type Status string; const (StatusActive Status = "active"; StatusDeleted Status = "deleted"; StatusPaid Status = "paid"; StatusSuspended Status = "suspended")`,
+			},
+		},
+		{
+			name:     "types",
+			typeInfo: resolvedTypes(fset, f),
+			want: []string{
+				`Kind admin 2 admin,guest const (KindAdmin Kind = "admin"; KindGuest Kind = "guest")`,
+				`User.Status active 2 active,deleted,suspended type Status string; const (StatusActive Status = "active"; StatusDeleted Status = "deleted"; StatusSuspended Status = "suspended")`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := RunWithConfig([]*ast.File{f}, fset, tt.typeInfo, &Config{
+				MinStringLength: 3,
+				MinOccurrences:  2,
+				Enums:           true,
+			})
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				if issue.Kind != KindEnum {
+					continue
+				}
+				got = append(got, fmt.Sprintf("%s %s %d %s %s", issue.Subject, issue.Str, issue.OccurrencesCount, strings.Join(issue.Values, ","), issue.Suggestion))
+			}
+			sort.Strings(got)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("issues =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestRunWithConfig_EnumCategories(t *testing.T) {
+	code := `package example
+func check(mode string) bool {
+	if mode == "READ_ONLY" {
+		return true
+	}
+	return mode != "READ_WRITE"
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	// The filters apply to the compared literals, not to the subject
+	for _, tt := range []struct {
+		only []string
+		want int
+	}{{[]string{"env-var"}, 1}, {[]string{"other"}, 0}} {
+		issues, err := RunWithConfig([]*ast.File{f}, fset, nil, &Config{
+			MinStringLength: 3,
+			MinOccurrences:  2,
+			Enums:           true,
+			OnlyCategories:  tt.only,
+		})
+		if err != nil {
+			t.Fatalf("RunWithConfig() error = %v", err)
+		}
+		var got []Issue
+		for _, issue := range issues {
+			if issue.Kind == KindEnum {
+				got = append(got, issue)
+			}
+		}
+		if len(got) != tt.want {
+			t.Fatalf("OnlyCategories %v: got %d enum issues, want %d", tt.only, len(got), tt.want)
+		}
+		if len(got) > 0 && (got[0].Subject != "mode" || got[0].Str != "READ_ONLY" || got[0].Category != CategoryEnvVar) {
+			t.Errorf("enum issue = %+v, want subject mode and literal READ_ONLY", got[0])
+		}
+	}
+}
+
+func TestEnumDeclaration(t *testing.T) {
+	tests := []struct {
+		subject enumSubject
+		values  []string
+		want    string
+	}{
+		{enumSubject{name: "orderState"}, []string{"NEW", "in-progress"}, `type OrderState string; const (OrderStateNew OrderState = "NEW"; OrderStateInProgress OrderState = "in-progress")`},
+		{enumSubject{name: "Level", typeName: "Level"}, []string{"-", "debug"}, `const (Level1 Level = "-"; LevelDebug Level = "debug")`},
+		{enumSubject{name: "state"}, []string{"in-progress", "in_progress", "inProgress"}, `type State string; const (StateInProgress State = "in-progress"; StateInProgress2 State = "in_progress"; StateInprogress State = "inProgress")`},
+		{enumSubject{name: "state"}, []string{"OK", "ok", "ok!"}, `type State string; const (StateOk State = "OK"; StateOk2 State = "ok"; StateOk3 State = "ok!")`},
+	}
+	for _, tt := range tests {
+		if got := enumDeclaration(&tt.subject, tt.values); got != tt.want {
+			t.Errorf("enumDeclaration(%q, %v) = %q, want %q", tt.subject.name, tt.values, got, tt.want)
+		}
+	}
+}
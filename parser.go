@@ -7,14 +7,17 @@
 package goconst
 
 import (
+	"fmt"
 	"go/ast"
 	"go/constant"
+	"go/importer"
 	"go/parser"
 	"go/token"
 	"go/types"
 	"io"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
@@ -116,11 +119,33 @@ type Parser struct {
 	// Meant to be passed via New()
 	path, ignore, ignoreStrings string
 	ignoreTests, matchConstant  bool
+	testConstants               bool
+	testFilePatterns            []string
+	testPackagePatterns         []string
+	sentinelErrors              bool
+	configKeys                  bool
+	configKeyRules              []configKeyRule
+	configKeyDocs               []string
+	contextKeys                 bool
+	enums                       bool
 	findDuplicates              bool
 	minLength, minOccurrences   int
+	minLengthByType             map[Type]int
+	minOccurrencesByType        map[Type]int
+	scope                       Scope
+	tableTests                  TableTestMode
+	minFiles, minPackages       int
+	categoryRules               []categoryRule
+	ignoreCategories            map[string]bool
+	onlyCategories              map[string]bool
 	numberMin, numberMax        int
 	excludeTypes                map[Type]bool
 	ignoreFunctions             map[string]struct{}
+	ignoreFunctionPatterns      []string
+	ignoreArgs                  []argRule
+	ignoreComposites            []compositeRule
+	includeComposites           []compositeRule
+	ignoreInFunctions           []string
 	maxConcurrency              int
 	evalConstExpressions        bool // Whether to evaluate constant expressions
 
@@ -132,6 +157,8 @@ type Parser struct {
 	consts      Constants
 	stringMutex sync.RWMutex
 	constMutex  sync.RWMutex
+	fileCount   int
+	literals    int
 
 	// Pre-compiled regexes for efficiency
 	ignoreRegex        *regexp.Regexp
@@ -143,6 +170,17 @@ type Parser struct {
 	stringCount      map[string]int
 	stringCountMutex sync.RWMutex
 
+	// Literals found by the opt-in detectors
+	errorMessages    map[string][]ExtendedPos
+	errorComparisons map[string][]ExtendedPos
+	configKeyUses    map[string]*configKeyUses
+	contextKeyUses   map[string][]ExtendedPos
+	enumSubjects     map[string]*enumSubject
+	detectorMutex    sync.Mutex
+
+	// Classification of the files matching testFilePatterns
+	testFileCache sync.Map
+
 	// Batch processing options
 	batchSize      int
 	enableBatching bool
@@ -266,23 +304,275 @@ func (p *Parser) EnableBatchProcessing(batchSize int) {
 }
 
 // SetIgnoreFunctions configures which function calls should have their string
-// arguments ignored. Supports direct calls (e.g., "println") and one-level
-// qualified calls (e.g., "slog.Info", "fmt.Errorf").
+// arguments ignored. Names are matched against the call as written (e.g.
+// "println", "slog.Info") and, when type information is available, against
+// the resolved callee: "log/slog.Info" for functions and "(*testing.T).Errorf"
+// for methods. Names may contain path.Match wildcards, such as "fmt.*" or
+// "(*go.uber.org/zap.Logger).*".
+//
+// A name followed by "#" only ignores some arguments: "fmt.Errorf#0" by
+// index, "slog.Info#msg" by parameter name and "slog.Info#..." the variadic
+// tail. Parameters are resolved from the callee's signature, so they need
+// type information. The name may be omitted: "#format" ignores the
+// parameters named format of any function. Only the resolved names and the
+// parameter rules load the imports, which slows the analysis down.
 func (p *Parser) SetIgnoreFunctions(names []string) {
+	p.ignoreFunctions = nil
+	p.ignoreFunctionPatterns = nil
+	p.ignoreArgs = nil
 	if len(names) == 0 {
-		p.ignoreFunctions = nil
 		return
 	}
 	m := make(map[string]struct{}, len(names))
 	for _, name := range names {
-		name = strings.TrimSpace(name)
-		if name != "" {
-			m[name] = struct{}{}
+		name, arg, hasArg := strings.Cut(strings.TrimSpace(name), "#")
+		if name == "" && !hasArg {
+			continue
+		}
+		// The receiver marker of "(*T).M" is not a wildcard
+		pattern := strings.ReplaceAll(name, "(*", `(\*`)
+		if hasArg {
+			p.ignoreArgs = append(p.ignoreArgs, newArgRule(pattern, arg))
+			continue
 		}
+		if strings.ContainsAny(strings.ReplaceAll(pattern, `\*`, ""), "*?[") {
+			p.ignoreFunctionPatterns = append(p.ignoreFunctionPatterns, pattern)
+			continue
+		}
+		m[name] = struct{}{}
 	}
 	p.ignoreFunctions = m
 }
 
+// argRule selects the arguments to ignore in the calls to the functions
+// matching pattern, every function when it is empty.
+type argRule struct {
+	pattern  string
+	index    int    // argument index, -1 when unused
+	param    string // parameter name
+	variadic bool   // the variadic tail
+}
+
+func newArgRule(pattern, arg string) argRule {
+	rule := argRule{pattern: pattern, index: -1}
+	if index, err := strconv.Atoi(arg); err == nil {
+		rule.index = index
+	} else if arg == "..." {
+		rule.variadic = true
+	} else {
+		rule.param = arg
+	}
+	return rule
+}
+
+// matches reports whether the rule applies to a callee known by names.
+func (r argRule) matches(names []string) bool {
+	return r.pattern == "" || matchAny(r.pattern, names)
+}
+
+// ignoredArgs returns the indexes of the arguments ignored in a call with
+// argc arguments to a callee known by names. sig is the callee's signature,
+// nil when unknown.
+func (p *Parser) ignoredArgs(names []string, sig *types.Signature, argc int) map[int]bool {
+	var ignored map[int]bool
+	ignore := func(from, to int) {
+		if ignored == nil {
+			ignored = make(map[int]bool)
+		}
+		for i := from; i < to && i < argc; i++ {
+			ignored[i] = true
+		}
+	}
+
+	for _, rule := range p.ignoreArgs {
+		if !rule.matches(names) {
+			continue
+		}
+		if rule.index >= 0 {
+			ignore(rule.index, rule.index+1)
+			continue
+		}
+		if sig == nil {
+			continue
+		}
+		params := sig.Params()
+		last := params.Len() - 1
+		if rule.variadic && sig.Variadic() {
+			ignore(last, argc)
+			continue
+		}
+		for i := 0; i < params.Len(); i++ {
+			if rule.param != "" && params.At(i).Name() == rule.param {
+				if i == last && sig.Variadic() {
+					ignore(i, argc)
+				} else {
+					ignore(i, i+1)
+				}
+			}
+		}
+	}
+	return ignored
+}
+
+// ignoresCalls reports whether calls to some functions are ignored.
+func (p *Parser) ignoresCalls() bool {
+	return len(p.ignoreFunctions) > 0 || len(p.ignoreFunctionPatterns) > 0 || len(p.ignoreArgs) > 0
+}
+
+// resolvesCalls reports whether some ignored calls can only be matched
+// against their resolved callee: names with an import path or a receiver,
+// and arguments selected by parameter. Other names match the call as
+// written, without loading the imports.
+func (p *Parser) resolvesCalls() bool {
+	for name := range p.ignoreFunctions {
+		if resolvedName(name) {
+			return true
+		}
+	}
+	for _, pattern := range p.ignoreFunctionPatterns {
+		if resolvedName(pattern) {
+			return true
+		}
+	}
+	for _, rule := range p.ignoreArgs {
+		if rule.index < 0 || resolvedName(rule.pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvedName reports whether a callee name has an import path, as in
+// "log/slog.Info", or a receiver, as in "(*testing.T).Errorf".
+func resolvedName(name string) bool {
+	return strings.HasPrefix(name, "(") || strings.Contains(name, "/")
+}
+
+// ignoredCall reports whether any of the names of a callee is ignored.
+func (p *Parser) ignoredCall(names []string) bool {
+	for _, name := range names {
+		if _, found := p.ignoreFunctions[name]; found {
+			return true
+		}
+		for _, pattern := range p.ignoreFunctionPatterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetMinOccurrencesByType overrides the minimum number of occurrences for
+// literals found in the given contexts. A literal found in several contexts
+// is reported once it reaches the highest of their thresholds.
+func (p *Parser) SetMinOccurrencesByType(thresholds map[Type]int) {
+	p.minOccurrencesByType = thresholds
+}
+
+// SetMinLengthByType overrides the minimum length of literals found in the
+// given contexts.
+func (p *Parser) SetMinLengthByType(thresholds map[Type]int) {
+	p.minLengthByType = thresholds
+}
+
+// SetScope sets the scope occurrences are counted and reported in.
+// Test and non-test code are always counted separately.
+func (p *Parser) SetScope(scope Scope) {
+	p.scope = scope
+}
+
+// SetTestConstants reports the literals of test files that equal a
+// production constant the test could use, exported or declared in the same
+// package, even below the occurrence thresholds. A test hard-coding the
+// value would keep passing after the constant changes.
+func (p *Parser) SetTestConstants(enabled bool) {
+	p.testConstants = enabled
+}
+
+// SetMinSpread sets the minimum number of distinct files and packages a
+// literal must appear in, within its scope, to be reported. Zero disables
+// the corresponding check.
+func (p *Parser) SetMinSpread(files, packages int) {
+	p.minFiles = files
+	p.minPackages = packages
+}
+
+// minLengthFor returns the minimum length of literals found in the given context.
+func (p *Parser) minLengthFor(typ Type) int {
+	if n, ok := p.minLengthByType[typ]; ok {
+		return n
+	}
+	return p.minLength
+}
+
+// minConstLength returns the minimum length of constants worth collecting:
+// the lowest length a literal can be reported with in any context.
+func (p *Parser) minConstLength() int {
+	lowest := p.minLength
+	for _, n := range p.minLengthByType {
+		if n < lowest {
+			lowest = n
+		}
+	}
+	return lowest
+}
+
+// minOccurrencesFor returns the number of occurrences a literal found at
+// the given positions needs to be reported: the highest threshold among the
+// contexts it appears in, so an occurrence in a lenient context does not
+// lower the bar for the others.
+func (p *Parser) minOccurrencesFor(positions []ExtendedPos) int {
+	if len(p.minOccurrencesByType) == 0 {
+		return p.minOccurrences
+	}
+
+	highest := -1
+	for _, pos := range positions {
+		n, ok := p.minOccurrencesByType[pos.Context]
+		if !ok {
+			n = p.minOccurrences
+		}
+		if n > highest {
+			highest = n
+		}
+	}
+	if highest < 0 {
+		return p.minOccurrences
+	}
+	return highest
+}
+
+// SetIgnoreInFunctions configures the functions whose bodies are skipped.
+// Patterns use path.Match syntax and are matched against the function name,
+// "Recv.Name" for methods: "Test*" skips test functions, "init" the package
+// initializers and "Server.*" every method of Server.
+func (p *Parser) SetIgnoreInFunctions(patterns []string) error {
+	var valid []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid function pattern %q: %w", pattern, err)
+		}
+		valid = append(valid, pattern)
+	}
+	p.ignoreInFunctions = valid
+	return nil
+}
+
+// ignoredFunction reports whether the body of the named function is skipped.
+func (p *Parser) ignoredFunction(name string) bool {
+	for _, pattern := range p.ignoreInFunctions {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // ParseTree will search the given path for occurrences that could be moved into constants.
 // If "..." is appended, the search will be recursive.
 //
@@ -325,17 +615,10 @@ func (p *Parser) parseTreeConcurrent(rootPath string, recursive bool) (Strings,
 			return nil, nil, err
 		}
 		// run type checker
-		info := &types.Info{
-			Types: make(map[ast.Expr]types.TypeAndValue),
-		}
-
-		chkConfig := &types.Config{
-			Error: func(err error) {}, // type checking is only used to evaluate constant expressions, so we ignore most errors
-		}
-		pkg := types.NewPackage("", f.Name.Name)
-		_ = types.NewChecker(chkConfig, fset, pkg, info).Files([]*ast.File{f})
+		info := p.typeCheck(fset, map[string][]*ast.File{f.Name.Name: {f}})
 
 		// Process the file
+		p.fileCount++
 		ast.Walk(&treeVisitor{
 			fileSet:     fset,
 			packageName: f.Name.Name,
@@ -376,7 +659,7 @@ func (p *Parser) parseTreeConcurrent(rootPath string, recursive bool) (Strings,
 				path := filepath.Join(rootPath, entry.Name())
 				if strings.HasSuffix(path, ".go") {
 					// Skip test files if configured
-					if p.ignoreTests && strings.HasSuffix(path, testSuffix) {
+					if p.ignoreTests && p.isTest(path, "") {
 						continue
 					}
 
@@ -409,7 +692,7 @@ func (p *Parser) parseTreeConcurrent(rootPath string, recursive bool) (Strings,
 			// Only process Go files
 			if strings.HasSuffix(path, ".go") {
 				// Skip test files if configured
-				if p.ignoreTests && strings.HasSuffix(path, testSuffix) {
+				if p.ignoreTests && p.isTest(path, "") {
 					return nil
 				}
 
@@ -436,18 +719,7 @@ func (p *Parser) parseTreeConcurrent(rootPath string, recursive bool) (Strings,
 	wg.Wait()
 
 	// Type checking must be performed serially to avoid data races.
-	info := &types.Info{
-		Types: make(map[ast.Expr]types.TypeAndValue),
-	}
-
-	chkConfig := &types.Config{
-		Error: func(err error) {}, // type checking is only used to evaluate constant expressions, so we ignore most errors
-	}
-
-	for pkgName, files := range filesByPackage {
-		chk := types.NewChecker(chkConfig, fset, types.NewPackage("", pkgName), info)
-		_ = chk.Files(files)
-	}
+	info := p.typeCheck(fset, filesByPackage)
 
 	// Visit all files
 	p.visitConcurrently(fset, info, filesByPackage)
@@ -523,6 +795,53 @@ func (p *Parser) parseConcurrently(filesChan <-chan string) (*token.FileSet, map
 	return fset, packageFiles
 }
 
+// typeCheck type-checks the files of each package and returns the collected
+// information. Type errors are ignored: the results are only used to evaluate
+// constant expressions and to resolve the callees of ignored calls, error
+// constructors and config key accessors, and the types of composite literals, contexts and
+// compared values. Imports are only loaded in the latter cases, and for
+// ignored calls when a rule needs the resolved callee, as they slow the
+// analysis down. The other ignored calls only need the import path of the
+// package names, known without loading them.
+func (p *Parser) typeCheck(fset *token.FileSet, filesByPackage map[string][]*ast.File) *types.Info {
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+	}
+
+	chkConfig := &types.Config{
+		Error: func(err error) {},
+	}
+	if p.ignoresCalls() {
+		info.Uses = make(map[*ast.Ident]types.Object)
+	}
+	if p.resolvesCalls() || p.hasCompositeRules() || p.sentinelErrors || p.configKeys || p.contextKeys || p.enums {
+		info.Uses = make(map[*ast.Ident]types.Object)
+		info.Selections = make(map[*ast.SelectorExpr]*types.Selection)
+		chkConfig.Importer = fallbackImporter{importer.Default(), importer.ForCompiler(fset, "source", nil)}
+	}
+
+	for pkgName, files := range filesByPackage {
+		chk := types.NewChecker(chkConfig, fset, types.NewPackage("", pkgName), info)
+		_ = chk.Files(files)
+	}
+	return info
+}
+
+// fallbackImporter tries each importer in turn. Export data is only found
+// for the standard library, other packages are imported from source.
+type fallbackImporter []types.Importer
+
+func (f fallbackImporter) Import(path string) (*types.Package, error) {
+	var err error
+	for _, imp := range f {
+		var pkg *types.Package
+		if pkg, err = imp.Import(path); err == nil {
+			return pkg, nil
+		}
+	}
+	return nil, err
+}
+
 // visitConcurrently visits all files in filesByPackage on a worker pool goroutines.
 func (p *Parser) visitConcurrently(fset *token.FileSet, info *types.Info, filesByPackage map[string][]*ast.File) {
 	var visitorWg sync.WaitGroup
@@ -549,6 +868,7 @@ func (p *Parser) visitConcurrently(fset *token.FileSet, info *types.Info, filesB
 
 	for pkgName, files := range filesByPackage {
 		for _, f := range files {
+			p.fileCount++ // safe since this is single-threaded.
 			parsedFilesChan <- parsedFile{pkgName, f}
 		}
 	}
@@ -579,7 +899,7 @@ func (p *Parser) parseTreeBatched(rootPath string, recursive bool) (Strings, Con
 			// Only process Go files
 			if !info.IsDir() && strings.HasSuffix(path, ".go") {
 				// Skip test files if configured to do so
-				if p.ignoreTests && strings.HasSuffix(path, testSuffix) {
+				if p.ignoreTests && p.isTest(path, "") {
 					return nil
 				}
 
@@ -616,7 +936,7 @@ func (p *Parser) parseTreeBatched(rootPath string, recursive bool) (Strings, Con
 			// Only process Go files
 			if strings.HasSuffix(path, ".go") {
 				// Skip test files if configured to do so
-				if p.ignoreTests && strings.HasSuffix(path, testSuffix) {
+				if p.ignoreTests && p.isTest(path, "") {
 					continue
 				}
 
@@ -682,17 +1002,7 @@ func (p *Parser) parseTreeBatched(rootPath string, recursive bool) (Strings, Con
 		fset, filesByPackage := p.parseConcurrently(fileChan)
 
 		// Type check -- must be processed serially to avoid data races
-		info := &types.Info{
-			Types: make(map[ast.Expr]types.TypeAndValue),
-		}
-
-		chkConfig := &types.Config{
-			Error: func(err error) {}, // type checking is only used to evaluate constant expressions, so we ignore most errors
-		}
-		for pkgName, files := range filesByPackage {
-			chk := types.NewChecker(chkConfig, fset, types.NewPackage("", pkgName), info)
-			_ = chk.Files(files)
-		}
+		info := p.typeCheck(fset, filesByPackage)
 
 		// Visit all files concurrently
 		p.visitConcurrently(fset, info, filesByPackage)
@@ -783,6 +1093,20 @@ func (p *Parser) shouldSkipPath(path string) bool {
 	return false
 }
 
+// FileCount returns the number of files analyzed by ParseTree or RunWithConfig.
+func (p *Parser) FileCount() int {
+	return p.fileCount
+}
+
+// LiteralCount returns the number of literals collected by ParseTree or
+// RunWithConfig, before the minimum occurrences threshold is applied.
+func (p *Parser) LiteralCount() int {
+	p.stringMutex.RLock()
+	defer p.stringMutex.RUnlock()
+
+	return p.literals
+}
+
 // IncrementStringCount safely increments the count for a string and returns the new count
 func (p *Parser) IncrementStringCount(str string) int {
 	p.stringCountMutex.Lock()
@@ -816,7 +1140,7 @@ func (p *Parser) ProcessResults() {
 	for str := range p.strs {
 		// Check count first as it's faster than looking at slice length
 		count := p.stringCount[str]
-		if count < p.minOccurrences {
+		if count < p.minOccurrencesFor(p.strs[str]) && !p.testConstantCandidate(str) {
 			delete(p.strs, str)
 			delete(p.stringCount, str)
 			continue
@@ -884,6 +1208,30 @@ type ExtendedPos struct {
 	// Interned package name to reduce memory usage when many positions
 	// reference the same package
 	packageName string
+	// Context is the kind of expression the literal appears in
+	Context Type
+	// Name of the enclosing function declaration, "Recv.Name" for methods
+	function string
+	// Position of the enclosing table of test cases, NoPos outside of tables
+	table token.Pos
+}
+
+// PackageName returns the name of the package the literal was found in.
+func (p ExtendedPos) PackageName() string {
+	return p.packageName
+}
+
+// Function returns the name of the function declaration the literal was
+// found in, "Recv.Name" for methods. It is empty outside of functions.
+func (p ExtendedPos) Function() string {
+	return p.function
+}
+
+// InTableTest reports whether the literal belongs to the cases of a
+// table-driven test. It is only tracked when table tests are not included
+// with the other test literals, see Parser.SetTableTests.
+func (p ExtendedPos) InTableTest() bool {
+	return p.table != token.NoPos
 }
 
 // Type represents the context in which a string literal appears.
@@ -904,3 +1252,69 @@ const (
 	// (e.g., []string{"foo"}, map[string]string{"k": "v"}, MyStruct{Field: "foo"})
 	CompositeLit
 )
+
+var typeNames = [...]string{
+	Assignment:   "assignment",
+	Binary:       "binary",
+	Case:         "case",
+	Return:       "return",
+	Call:         "call",
+	CompositeLit: "composite-lit",
+}
+
+// String returns the lower-case name of the context type, as used in
+// the CLI flags and reports.
+func (t Type) String() string {
+	if t < 0 || int(t) >= len(typeNames) {
+		return "Type(" + strconv.Itoa(int(t)) + ")"
+	}
+	return typeNames[t]
+}
+
+// Scope is the part of the code occurrences are counted in.
+type Scope int
+
+const (
+	// ScopeModule counts occurrences across everything the parser saw
+	ScopeModule Scope = iota
+	// ScopePackage counts occurrences per package directory
+	ScopePackage
+	// ScopeFile counts occurrences per file
+	ScopeFile
+	// ScopeFunction counts occurrences per function declaration
+	ScopeFunction
+)
+
+var scopeNames = [...]string{
+	ScopeModule:   "module",
+	ScopePackage:  "package",
+	ScopeFile:     "file",
+	ScopeFunction: "function",
+}
+
+func (s Scope) String() string {
+	if s < 0 || int(s) >= len(scopeNames) {
+		return "Scope(" + strconv.Itoa(int(s)) + ")"
+	}
+	return scopeNames[s]
+}
+
+// ParseScope returns the Scope with the given name, as returned by String.
+func ParseScope(name string) (Scope, error) {
+	for s, scopeName := range scopeNames {
+		if scopeName == name {
+			return Scope(s), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown scope %q, expected one of: %s", name, strings.Join(scopeNames[:], ", "))
+}
+
+// ParseType returns the Type with the given name, as returned by String.
+func ParseType(name string) (Type, error) {
+	for t, typeName := range typeNames {
+		if typeName == name {
+			return Type(t), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown context type %q, expected one of: %s", name, strings.Join(typeNames[:], ", "))
+}
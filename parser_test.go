@@ -367,6 +367,15 @@ func nested() {
 				t.Logf("Found: %q with %d occurrences", str, len(occurrences))
 			}
 		}
+		if got := p.FileCount(); got != 2 {
+			t.Errorf("FileCount() = %d, expected 2", got)
+		}
+		if got := p.LiteralCount(); got != 4 {
+			t.Errorf("LiteralCount() = %d, expected 4", got)
+		}
+		if pkg := strs["nested"][0].PackageName(); pkg != "nested" {
+			t.Errorf("PackageName() = %q, expected nested", pkg)
+		}
 	})
 
 	// Test ignoreTests flag
@@ -971,3 +980,105 @@ func generateLargeGoFile(lineCount int) string {
 
 	return b.String()
 }
+
+func TestTypeString(t *testing.T) {
+	tests := map[Type]string{
+		Assignment:   "assignment",
+		Binary:       "binary",
+		Case:         "case",
+		Return:       "return",
+		Call:         "call",
+		CompositeLit: "composite-lit",
+		Type(42):     "Type(42)",
+	}
+	for typ, want := range tests {
+		if got := typ.String(); got != want {
+			t.Errorf("Type(%d).String() = %q, want %q", int(typ), got, want)
+		}
+	}
+}
+
+func TestParseType(t *testing.T) {
+	for typ := Assignment; typ <= CompositeLit; typ++ {
+		got, err := ParseType(typ.String())
+		if err != nil || got != typ {
+			t.Errorf("ParseType(%q) = %v, %v, want %v", typ.String(), got, err, typ)
+		}
+	}
+	if _, err := ParseType("switch"); err == nil {
+		t.Error("ParseType() should reject unknown names")
+	}
+}
+
+func TestParser_IgnoredCall(t *testing.T) {
+	p := &Parser{}
+	p.SetIgnoreFunctions([]string{"println", " fmt.* ", "(*testing.T).Errorf", "(*go.uber.org/zap.Logger).*", ""})
+
+	tests := map[string]bool{
+		"println":                         true,
+		"fmt.Sprintf":                     true,
+		"fmt":                             false,
+		"(*testing.T).Errorf":             true,
+		"(testing.T).Errorf":              false,
+		"(*testing.T).Logf":               false,
+		"(*go.uber.org/zap.Logger).Info":  true,
+		"(go.uber.org/zap.Logger).Info":   false,
+		"(*go.uber.org/zap.Sugared).Info": false,
+	}
+	for name, want := range tests {
+		if got := p.ignoredCall([]string{name}); got != want {
+			t.Errorf("ignoredCall(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	p.SetIgnoreFunctions(nil)
+	if p.ignoresCalls() {
+		t.Error("an empty list should not ignore calls")
+	}
+}
+
+func TestParser_ResolvesCalls(t *testing.T) {
+	tests := map[string]bool{
+		"println":                     false,
+		"fmt.*":                       false,
+		"fmt.Errorf#0":                false,
+		"log/slog.Info":               true,
+		"(*testing.T).Errorf":         true,
+		"(*go.uber.org/zap.Logger).*": true,
+		"slog.Info#msg":               true,
+		"#...":                        true,
+	}
+	for name, want := range tests {
+		p := &Parser{}
+		p.SetIgnoreFunctions([]string{name})
+		if got := p.resolvesCalls(); got != want {
+			t.Errorf("resolvesCalls() with %q = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParser_IgnoredArgs(t *testing.T) {
+	p := &Parser{}
+	p.SetIgnoreFunctions([]string{"fmt.Errorf#0", "slog.Info#msg", "log.*#1"})
+
+	if len(p.ignoreArgs) != 3 || len(p.ignoreFunctions) != 0 {
+		t.Fatalf("argument rules should not ignore whole calls: %+v", p)
+	}
+	if p.ignoredCall([]string{"fmt.Errorf"}) {
+		t.Error("fmt.Errorf#0 should not ignore the whole call")
+	}
+
+	if got := p.ignoredArgs([]string{"fmt.Errorf"}, nil, 3); !got[0] || got[1] || got[2] {
+		t.Errorf("ignoredArgs(fmt.Errorf) = %v, want [0]", got)
+	}
+	if got := p.ignoredArgs([]string{"log.Printf"}, nil, 3); len(got) != 1 || !got[1] {
+		t.Errorf("ignoredArgs(log.Printf) = %v, want [1]", got)
+	}
+	// Parameter names need the signature
+	if got := p.ignoredArgs([]string{"slog.Info"}, nil, 3); got != nil {
+		t.Errorf("ignoredArgs(slog.Info) without signature = %v, want none", got)
+	}
+	if got := p.ignoredArgs([]string{"fmt.Println"}, nil, 3); got != nil {
+		t.Errorf("ignoredArgs(fmt.Println) = %v, want none", got)
+	}
+}
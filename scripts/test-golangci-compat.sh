@@ -122,14 +122,14 @@ fi
 # Test 4: Test with JSON output (golangci-lint compatibility)
 echo "Test 4: Testing JSON output format..."
 "$GOCONST_BIN" -ignore-strings "test-ignore" -match-constant -output json "$TEST_DIR/testpkg" > "$TEST_DIR/output4.json"
-# Check that the JSON has the correct structure: strings + constants sections
-if ! grep -q '"constants".*"test-const"' "$TEST_DIR/output4.json"; then
-    echo "Failed: JSON output should include constants with test-const"
+# Check that the JSON has the correct structure: a versioned report with an issue list
+if ! grep -q '"version":1.*"issues".*"str":"test-const"' "$TEST_DIR/output4.json"; then
+    echo "Failed: JSON output should include an issue for test-const"
     cat "$TEST_DIR/output4.json"
     exit 1
 fi
 
-if ! grep -q '"Name":"ExistingConst"' "$TEST_DIR/output4.json"; then
+if ! grep -q '"matching_const":"ExistingConst"' "$TEST_DIR/output4.json"; then
     echo "Failed: JSON output should include ExistingConst"
     cat "$TEST_DIR/output4.json"
     exit 1
@@ -0,0 +1,213 @@
+package goconst
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// errorInterface is the type of the built-in error interface.
+var errorInterface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// errorStringFuncs are the functions looking for a literal in an error
+// message, as in strings.Contains(err.Error(), "timeout").
+var errorStringFuncs = map[string]bool{
+	"strings.Contains":  true,
+	"strings.HasPrefix": true,
+	"strings.HasSuffix": true,
+	"strings.EqualFold": true,
+	"strings.Index":     true,
+}
+
+// SetSentinelErrors enables the detection of error messages that would be
+// better served by sentinel errors. Identical messages passed to errors.New,
+// or to fmt.Errorf without formatting verbs, in at least MinOccurrences
+// places are reported as KindSentinelError. Every comparison of err.Error()
+// with a literal, with == and != or functions such as strings.Contains, is
+// reported as KindErrorComparison. These literals are no longer reported as
+// repeated strings.
+func (p *Parser) SetSentinelErrors(enabled bool) {
+	p.sentinelErrors = enabled
+	if enabled && p.errorMessages == nil {
+		p.errorMessages = make(map[string][]ExtendedPos)
+		p.errorComparisons = make(map[string][]ExtendedPos)
+	}
+}
+
+// addErrorMessage records the message of a call creating an error from a
+// constant message. It reports whether the call was one.
+func (v *treeVisitor) addErrorMessage(call *ast.CallExpr) bool {
+	if len(call.Args) != 1 {
+		return false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return false
+	}
+
+	names := v.callNames(call.Fun)
+	switch {
+	case containsName(names, "errors.New"):
+	case containsName(names, "fmt.Errorf"):
+		if msg, err := strconv.Unquote(lit.Value); err != nil || hasFormatVerb(msg) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	v.addDetected(v.p.errorMessages, lit, Call)
+	return true
+}
+
+// addErrorComparison records the literal of a comparison with err.Error(),
+// either as the operand of a binary expression or as the argument of a
+// function such as strings.Contains. It reports whether node was one.
+func (v *treeVisitor) addErrorComparison(node ast.Node) bool {
+	var x, y ast.Expr
+	typ := Binary
+	switch t := node.(type) {
+	case *ast.BinaryExpr:
+		x, y = t.X, t.Y
+	case *ast.CallExpr:
+		if len(t.Args) != 2 || !v.isErrorStringFunc(t.Fun) {
+			return false
+		}
+		x, y, typ = t.Args[0], t.Args[1], Call
+	default:
+		return false
+	}
+
+	if v.isErrorString(y) {
+		x, y = y, x
+	}
+	lit, ok := y.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING || !v.isErrorString(x) {
+		return false
+	}
+
+	v.addDetected(v.p.errorComparisons, lit, typ)
+	return true
+}
+
+func (v *treeVisitor) isErrorStringFunc(fun ast.Expr) bool {
+	for _, name := range v.callNames(fun) {
+		if errorStringFuncs[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrorString reports whether expr is a call to the Error method of an
+// error. Without type information, any Error method without arguments is.
+func (v *treeVisitor) isErrorString(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Error" {
+		return false
+	}
+	if v.typeInfo == nil {
+		return true
+	}
+	typ := v.typeInfo.TypeOf(sel.X)
+	if typ == nil || typ == types.Typ[types.Invalid] {
+		return true
+	}
+	return types.Implements(typ, errorInterface)
+}
+
+// addDetected records a string literal found by a detector, provided it is
+// not ignored by the -ignore-strings patterns.
+func (v *treeVisitor) addDetected(found map[string][]ExtendedPos, lit *ast.BasicLit, typ Type) {
+	str, err := strconv.Unquote(lit.Value)
+	if err != nil || str == "" {
+		return
+	}
+	if v.ignoreRegex != nil && v.ignoreRegex.MatchString(str) {
+		return
+	}
+
+	str = InternString(str)
+	v.p.detectorMutex.Lock()
+	defer v.p.detectorMutex.Unlock()
+	found[str] = append(found[str], v.position(lit.Pos(), typ))
+}
+
+// sentinelIssues returns the issues of the sentinel error detector.
+func (p *Parser) sentinelIssues() []Issue {
+	p.detectorMutex.Lock()
+	defer p.detectorMutex.Unlock()
+
+	var issues []Issue
+	for _, msg := range sortedKeys(p.errorMessages) {
+		issues = append(issues, p.scopedIssues(msg, p.errorMessages[msg], p.minOccurrences, false, Issue{
+			Kind:       KindSentinelError,
+			Suggestion: fmt.Sprintf("var %s = errors.New(%q)", sentinelName(msg), msg),
+		})...)
+	}
+	for _, msg := range sortedKeys(p.errorComparisons) {
+		issues = append(issues, p.scopedIssues(msg, p.errorComparisons[msg], 1, true, Issue{
+			Kind:       KindErrorComparison,
+			Suggestion: fmt.Sprintf("errors.Is(err, %s)", sentinelName(msg)),
+		})...)
+	}
+	return issues
+}
+
+// sentinelName derives the name of a sentinel error from its message, e.g.
+// ErrNotFound for "not found".
+func sentinelName(msg string) string {
+	return identifier("Err", msg, 4)
+}
+
+// identifier derives an exported identifier from the first max words of s,
+// every word when max is 0, e.g. EnvDatabaseUrl for "Env" and "DATABASE_URL".
+func identifier(prefix, s string, max int) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if max > 0 && len(words) > max {
+		words = words[:max]
+	}
+
+	var name strings.Builder
+	name.WriteString(prefix)
+	for _, word := range words {
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		name.WriteString(string(runes))
+	}
+	return name.String()
+}
+
+// hasFormatVerb reports whether a format string has any verb, "%%" aside.
+func hasFormatVerb(format string) bool {
+	return strings.Contains(strings.ReplaceAll(format, "%%", ""), "%")
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
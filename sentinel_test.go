@@ -0,0 +1,124 @@
+package goconst
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRunWithConfig_SentinelErrors(t *testing.T) {
+	code := `package example
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func find() error {
+	if true {
+		return errors.New("not found")
+	}
+	if false {
+		return fmt.Errorf("not found")
+	}
+	return fmt.Errorf("not found: %s", "key")
+}
+
+func check(err error) bool {
+	if err.Error() == "connection reset" {
+		return true
+	}
+	return strings.Contains(err.Error(), "connection reset") || "timeout" != err.Error()
+}
+
+func other() string {
+	return "connection reset"
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		want    []string
+	}{
+		{
+			name: "disabled",
+			want: []string{"example.go:11 not found", "example.go:20 connection reset"},
+		},
+		{
+			name:    "enabled",
+			enabled: true,
+			want: []string{
+				"example.go:11 not found sentinel-error var ErrNotFound = errors.New(\"not found\")",
+				"example.go:20 connection reset error-comparison errors.Is(err, ErrConnectionReset)",
+				"example.go:23 connection reset error-comparison errors.Is(err, ErrConnectionReset)",
+				"example.go:23 timeout error-comparison errors.Is(err, ErrTimeout)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := RunWithConfig([]*ast.File{f}, fset, nil, &Config{
+				MinStringLength: 3,
+				MinOccurrences:  2,
+				SentinelErrors:  tt.enabled,
+			})
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				desc := issue.Pos.String()
+				desc = desc[:strings.LastIndex(desc, ":")] + " " + issue.Str
+				if issue.Kind != "" {
+					desc += " " + issue.Kind + " " + issue.Suggestion
+				}
+				got = append(got, desc)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSentinelName(t *testing.T) {
+	tests := map[string]string{
+		"not found":                        "ErrNotFound",
+		"Invalid-Token":                    "ErrInvalidToken",
+		"user: permission denied for file": "ErrUserPermissionDeniedFor",
+		"HTTP 404":                         "ErrHttp404",
+		"...":                              "Err",
+	}
+	for msg, want := range tests {
+		if got := sentinelName(msg); got != want {
+			t.Errorf("sentinelName(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestHasFormatVerb(t *testing.T) {
+	tests := map[string]bool{
+		"not found":         false,
+		"100%% done":        false,
+		"not found: %s":     true,
+		"%d%% done":         true,
+		"trailing percent%": true,
+	}
+	for format, want := range tests {
+		if got := hasFormatVerb(format); got != want {
+			t.Errorf("hasFormatVerb(%q) = %v, want %v", format, got, want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package goconst
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// TableTestMode is how the literals of table-driven test cases are handled.
+type TableTestMode int
+
+const (
+	// TableTestsInclude counts them along with the other test literals
+	TableTestsInclude TableTestMode = iota
+	// TableTestsExclude ignores them
+	TableTestsExclude
+	// TableTestsSeparate counts them apart from the other test literals, and
+	// only reports the values repeated across several tables
+	TableTestsSeparate
+)
+
+var tableTestModeNames = [...]string{
+	TableTestsInclude:  "include",
+	TableTestsExclude:  "exclude",
+	TableTestsSeparate: "separate",
+}
+
+func (m TableTestMode) String() string {
+	if m < 0 || int(m) >= len(tableTestModeNames) {
+		return "TableTestMode(" + strconv.Itoa(int(m)) + ")"
+	}
+	return tableTestModeNames[m]
+}
+
+// ParseTableTestMode returns the TableTestMode with the given name, as
+// returned by String.
+func ParseTableTestMode(name string) (TableTestMode, error) {
+	for m, modeName := range tableTestModeNames {
+		if modeName == name {
+			return TableTestMode(m), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown table test mode %q, expected one of: %s", name, strings.Join(tableTestModeNames[:], ", "))
+}
+
+// SetTableTests sets how the literals of table-driven test cases are
+// handled. Tables are slices, arrays or maps of anonymous structs, declared
+// in test files, that are ranged over by a loop calling Run, as in:
+//
+//	tests := []struct{ name, input string }{...}
+//	for _, tt := range tests {
+//		t.Run(tt.name, func(t *testing.T) { ... })
+//	}
+func (p *Parser) SetTableTests(mode TableTestMode) {
+	p.tableTests = mode
+}
+
+// tableCount returns the number of distinct tables the positions are in.
+func tableCount(positions []ExtendedPos) int {
+	tables := make(map[token.Pos]bool)
+	for _, pos := range positions {
+		if pos.table != token.NoPos {
+			tables[pos.table] = true
+		}
+	}
+	return len(tables)
+}
+
+// findTestTables returns the composite literals of a file that hold
+// table-driven test cases. A table is ranged over either directly or through
+// a variable declared in the enclosing function or at the package level.
+func findTestTables(f *ast.File) map[*ast.CompositeLit]bool {
+	tables := make(map[*ast.CompositeLit]bool)
+	global := tableDecls(f)
+
+	ast.Inspect(f, func(node ast.Node) bool {
+		fn, ok := node.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			return true
+		}
+		local := tableDecls(fn.Body)
+		ast.Inspect(fn.Body, func(node ast.Node) bool {
+			rng, ok := node.(*ast.RangeStmt)
+			if !ok || !callsRun(rng.Body) {
+				return true
+			}
+			switch x := rng.X.(type) {
+			case *ast.CompositeLit:
+				if isTableType(x.Type) {
+					tables[x] = true
+				}
+			case *ast.Ident:
+				if lit := lastDeclBefore(local[x.Name], x.Pos()); lit != nil {
+					tables[lit] = true
+				} else if lit := lastDeclBefore(global[x.Name], token.NoPos); lit != nil {
+					tables[lit] = true
+				}
+			}
+			return true
+		})
+		return false
+	})
+	return tables
+}
+
+// tableDecls returns the table literals assigned to each variable name
+// within node, in source order. For a file, only the package level
+// declarations are considered.
+func tableDecls(node ast.Node) map[string][]*ast.CompositeLit {
+	decls := make(map[string][]*ast.CompositeLit)
+	add := func(names []ast.Expr, values []ast.Expr) {
+		if len(names) != len(values) {
+			return
+		}
+		for i, value := range values {
+			ident, ok := names[i].(*ast.Ident)
+			lit, isLit := value.(*ast.CompositeLit)
+			if ok && isLit && isTableType(lit.Type) {
+				decls[ident.Name] = append(decls[ident.Name], lit)
+			}
+		}
+	}
+
+	if f, ok := node.(*ast.File); ok {
+		for _, decl := range f.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+				addValueSpecs(gen, add)
+			}
+		}
+		return decls
+	}
+
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch t := node.(type) {
+		case *ast.AssignStmt:
+			add(t.Lhs, t.Rhs)
+		case *ast.GenDecl:
+			if t.Tok == token.VAR {
+				addValueSpecs(t, add)
+			}
+		}
+		return true
+	})
+	return decls
+}
+
+func addValueSpecs(gen *ast.GenDecl, add func(names, values []ast.Expr)) {
+	for _, spec := range gen.Specs {
+		vs := spec.(*ast.ValueSpec)
+		names := make([]ast.Expr, len(vs.Names))
+		for i, name := range vs.Names {
+			names[i] = name
+		}
+		add(names, vs.Values)
+	}
+}
+
+// lastDeclBefore returns the last of the literals declared before pos, the
+// last one when pos is token.NoPos.
+func lastDeclBefore(lits []*ast.CompositeLit, pos token.Pos) *ast.CompositeLit {
+	var last *ast.CompositeLit
+	for _, lit := range lits {
+		if pos == token.NoPos || lit.Pos() < pos {
+			last = lit
+		}
+	}
+	return last
+}
+
+// isTableType reports whether typ is a slice, array or map of anonymous
+// structs.
+func isTableType(typ ast.Expr) bool {
+	var elt ast.Expr
+	switch t := typ.(type) {
+	case *ast.ArrayType:
+		elt = t.Elt
+	case *ast.MapType:
+		elt = t.Value
+	default:
+		return false
+	}
+	if star, ok := elt.(*ast.StarExpr); ok {
+		elt = star.X
+	}
+	_, ok := elt.(*ast.StructType)
+	return ok
+}
+
+// callsRun reports whether body calls a Run method, such as t.Run or b.Run.
+func callsRun(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Run" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
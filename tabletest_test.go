@@ -0,0 +1,144 @@
+package goconst
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseTableTestMode(t *testing.T) {
+	for _, mode := range []TableTestMode{TableTestsInclude, TableTestsExclude, TableTestsSeparate} {
+		got, err := ParseTableTestMode(mode.String())
+		if err != nil || got != mode {
+			t.Errorf("ParseTableTestMode(%q) = %v, %v, want %v", mode.String(), got, err, mode)
+		}
+	}
+	if _, err := ParseTableTestMode("all"); err == nil {
+		t.Error("ParseTableTestMode should reject unknown modes")
+	}
+	if got := TableTestMode(7).String(); got != "TableTestMode(7)" {
+		t.Errorf("TableTestMode(7).String() = %q", got)
+	}
+}
+
+func TestFindTestTables(t *testing.T) {
+	code := `package example
+var global = []struct{ in string }{{"global"}}
+func TestGlobal(t *testing.T) {
+	for _, tt := range global {
+		t.Run(tt.in, func(t *testing.T) {})
+	}
+}
+func TestLocal(t *testing.T) {
+	tests := map[string]*struct{ in string }{"local": {"x"}}
+	for name := range tests {
+		t.Run(name, nil)
+	}
+}
+func TestInline(t *testing.T) {
+	for _, tt := range [...]struct{ in string }{{"inline"}} {
+		t.Run(tt.in, nil)
+	}
+}
+func TestNotRun(t *testing.T) {
+	values := []struct{ in string }{{"not-run"}}
+	for range values {
+	}
+}
+func TestNamed(t *testing.T) {
+	named := []testCase{{"named"}}
+	for _, tt := range named {
+		t.Run(tt.in, nil)
+	}
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example_test.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	var got []string
+	for lit := range findTestTables(f) {
+		got = append(got, fset.Position(lit.Pos()).String())
+	}
+	sort.Strings(got)
+	want := []string{"example_test.go:15:21", "example_test.go:2:14", "example_test.go:9:11"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("findTestTables() = %v, want %v", got, want)
+	}
+}
+
+func TestRunWithConfig_TableTests(t *testing.T) {
+	prod := `package example
+const Valid = "valid input"
+func f() {
+	_ = "shared"
+	_ = "shared"
+}`
+	test := `package example
+func TestA(t *testing.T) {
+	tests := []struct{ name, in string }{
+		{name: "valid input", in: "first"},
+		{name: "only here", in: "first"},
+		{name: "only here", in: "first"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {})
+	}
+	_ = "shared"
+	_ = "shared"
+}
+func TestB(t *testing.T) {
+	for _, tt := range []struct{ name string }{{"valid input"}} {
+		t.Run(tt.name, nil)
+	}
+}`
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, code := range map[string]string{"example.go": prod, "example_test.go": test} {
+		f, err := parser.ParseFile(fset, name, code, 0)
+		if err != nil {
+			t.Fatalf("Failed to parse: %v", err)
+		}
+		files = append(files, f)
+	}
+
+	tests := []struct {
+		mode TableTestMode
+		want []string
+	}{
+		{TableTestsInclude, []string{"example.go shared", "example_test.go first", "example_test.go only here", "example_test.go shared", "example_test.go valid input"}},
+		{TableTestsExclude, []string{"example.go shared", "example_test.go shared"}},
+		{TableTestsSeparate, []string{"example.go shared", "example_test.go shared", "example_test.go valid input table=Valid"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			issues, err := RunWithConfig(files, fset, nil, &Config{
+				MinStringLength:    3,
+				MinOccurrences:     2,
+				MatchWithConstants: true,
+				TableTests:         tt.mode,
+			})
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				desc := issue.Pos.Filename + " " + issue.Str
+				if issue.TableTest {
+					desc += " table=" + issue.MatchingConst
+				}
+				got = append(got, desc)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package goconst
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SetTestPatterns extends the classification of test code beyond the
+// "_test.go" files, which are always test code. files are path.Match
+// patterns matched against the base name, every directory name and every
+// run of consecutive path segments of a file: "*_mock.go" matches mocks,
+// "fakes" every file below a fakes directory and "internal/testutil" every
+// file below that path. The path is taken from the analyzed directory, from
+// the module root when analyzing files, so that the directories above them
+// never match. packages are path.Match patterns matched against package
+// names, such as "testutil" or "*test".
+//
+// The classification applies to IgnoreTests, to the separate counting of
+// test and production code, to constant matching and to duplicate
+// constants.
+func (p *Parser) SetTestPatterns(files, packages []string) error {
+	var err error
+	if p.testFilePatterns, err = testPatterns(files); err != nil {
+		return err
+	}
+	p.testPackagePatterns, err = testPatterns(packages)
+	return err
+}
+
+func testPatterns(patterns []string) ([]string, error) {
+	var valid []string
+	for _, pattern := range patterns {
+		pattern = strings.Trim(strings.TrimSpace(filepath.ToSlash(pattern)), "/")
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid test pattern %q: %w", pattern, err)
+		}
+		valid = append(valid, pattern)
+	}
+	return valid, nil
+}
+
+// isTest reports whether the file, of the given package, is test code.
+// pkg may be empty when the package is not known yet, in which case only
+// the file patterns apply.
+func (p *Parser) isTest(filename, pkg string) bool {
+	if strings.HasSuffix(filename, testSuffix) {
+		return true
+	}
+	if pkg != "" && p.isTestPackage(pkg) {
+		return true
+	}
+	if len(p.testFilePatterns) == 0 {
+		return false
+	}
+
+	if isTest, ok := p.testFileCache.Load(filename); ok {
+		return isTest.(bool)
+	}
+	isTest := p.matchesTestFile(filename)
+	p.testFileCache.Store(filename, isTest)
+	return isTest
+}
+
+// isTestPackage reports whether the package name matches the test package
+// patterns.
+func (p *Parser) isTestPackage(pkg string) bool {
+	return matchesAnyPattern(p.testPackagePatterns, pkg)
+}
+
+func (p *Parser) matchesTestFile(filename string) bool {
+	if abs, err := filepath.Abs(filename); err == nil {
+		if rel, err := filepath.Rel(p.testRoot(abs), abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			filename = rel
+		}
+	}
+
+	segments := strings.Split(strings.Trim(filepath.ToSlash(filename), "/"), "/")
+	for i := range segments {
+		for j := i + 1; j <= len(segments); j++ {
+			if matchesAnyPattern(p.testFilePatterns, strings.Join(segments[i:j], "/")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// testRoot returns the directory the test file patterns are matched from:
+// the analyzed directory, or the module root of the file when the parser
+// was given files, the file system root when there is none.
+func (p *Parser) testRoot(filename string) string {
+	if p.path != "" {
+		root := strings.TrimSuffix(p.path, "...")
+		if info, err := os.Stat(root); err == nil && info.IsDir() {
+			if abs, err := filepath.Abs(root); err == nil {
+				return abs
+			}
+		}
+	}
+
+	for dir := filepath.Dir(filename); ; {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+func matchesAnyPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
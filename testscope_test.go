@@ -0,0 +1,155 @@
+package goconst
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParser_IsTest(t *testing.T) {
+	p := &Parser{}
+	if err := p.SetTestPatterns([]string{"*_mock.go", "internal/testutil/", " e2e ", ""}, []string{"*test"}); err != nil {
+		t.Fatalf("SetTestPatterns() error = %v", err)
+	}
+
+	tests := []struct {
+		filename, pkg string
+		want          bool
+	}{
+		{"/repo/api/api_test.go", "api", true},
+		{"/repo/api/api.go", "api", false},
+		{"/repo/api/client_mock.go", "api", true},
+		{"/repo/internal/testutil/helpers.go", "testutil", true},
+		{"/repo/internal/testutil/sub/helpers.go", "sub", true},
+		{"/repo/internal/testutils/helpers.go", "testutils", false},
+		{"/repo/e2e/run.go", "", true},
+		{"/repo/api/apitest/server.go", "apitest", true},
+		{"/repo/api/apitest/server.go", "", false},
+	}
+	for _, tt := range tests {
+		if got := p.isTest(tt.filename, tt.pkg); got != tt.want {
+			t.Errorf("isTest(%q, %q) = %v, want %v", tt.filename, tt.pkg, got, tt.want)
+		}
+	}
+
+	if err := p.SetTestPatterns([]string{"[mock"}, nil); err == nil {
+		t.Error("SetTestPatterns() should reject malformed patterns")
+	}
+}
+
+func TestParser_IsTestFromRoot(t *testing.T) {
+	// The analyzed code lives below directories matching the patterns
+	tempDir := filepath.Join(t.TempDir(), "e2e", "fakes")
+	module := filepath.Join(tempDir, "module")
+	for _, dir := range []string{filepath.Join(module, "e2e"), filepath.Join(tempDir, "plain")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(module, "go.mod"), []byte("module example.com/m\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	tests := []struct {
+		name, path, filename string
+		want                 bool
+	}{
+		{"module root", "", filepath.Join(module, "app.go"), false},
+		{"below module root", "", filepath.Join(module, "e2e", "run.go"), true},
+		{"analyzed directory", filepath.Join(tempDir, "plain") + "/...", filepath.Join(tempDir, "plain", "app.go"), false},
+		{"analyzed file", filepath.Join(module, "e2e", "run.go"), filepath.Join(module, "e2e", "run.go"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{path: tt.path}
+			if err := p.SetTestPatterns([]string{"e2e", "fakes"}, nil); err != nil {
+				t.Fatalf("SetTestPatterns() error = %v", err)
+			}
+			if got := p.isTest(tt.filename, ""); got != tt.want {
+				t.Errorf("isTest(%q) = %v, want %v", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunWithConfig_TestPatterns(t *testing.T) {
+	sources := map[string]string{
+		"app/app.go":                    "package app\nconst Name = \"dup-const\"\nfunc f() { _ = \"shared\" }",
+		"app/client_mock.go":            "package app\nfunc g() { _ = \"shared\" }",
+		"internal/testutil/util.go":     "package testutil\nconst Name = \"dup-const\"\nfunc h() { _ = \"shared\" }",
+		"internal/fixtures/fixtures.go": "package fixturetest\nfunc i() { _ = \"shared\" }",
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range sources {
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatalf("Failed to parse: %v", err)
+		}
+		files = append(files, f)
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{
+			name: "default",
+			want: []string{
+				"app/app.go shared 4", "app/client_mock.go shared 4",
+				"internal/fixtures/fixtures.go shared 4", "internal/testutil/util.go dup-const", "internal/testutil/util.go shared 4",
+			},
+		},
+		{
+			name: "patterns",
+			cfg:  Config{TestFilePatterns: []string{"*_mock.go", "internal/testutil"}, TestPackagePatterns: []string{"*test"}},
+			want: []string{"app/client_mock.go shared 3", "internal/fixtures/fixtures.go shared 3", "internal/testutil/util.go shared 3"},
+		},
+		{
+			name: "ignored",
+			cfg: Config{
+				IgnoreTests:         true,
+				MinOccurrences:      1,
+				TestFilePatterns:    []string{"*_mock.go", "internal/testutil"},
+				TestPackagePatterns: []string{"*test"},
+			},
+			want: []string{"app/app.go shared 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.MinStringLength = 3
+			if cfg.MinOccurrences == 0 {
+				cfg.MinOccurrences = 2
+			}
+			cfg.FindDuplicates = true
+
+			issues, err := RunWithConfig(files, fset, nil, &cfg)
+			if err != nil {
+				t.Fatalf("RunWithConfig() error = %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				desc := issue.Pos.Filename + " " + issue.Str
+				if issue.DuplicateConst == "" {
+					desc += " " + strconv.Itoa(issue.OccurrencesCount)
+				}
+				got = append(got, desc)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
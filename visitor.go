@@ -18,6 +18,12 @@ type treeVisitor struct {
 	packageName string
 	p           *Parser
 	ignoreRegex *regexp.Regexp
+	// function is the name of the enclosing function declaration
+	function string
+	// tables are the table-driven test cases of the file
+	tables map[*ast.CompositeLit]bool
+	// table is the position of the enclosing table, NoPos outside of tables
+	table token.Pos
 }
 
 // Visit browses the AST tree for strings that could be potentially
@@ -32,9 +38,36 @@ func (v *treeVisitor) Visit(node ast.Node) ast.Visitor {
 	// but then we wouldn't be able to tell in which context
 	// the string is defined (could be a constant definition).
 	switch t := node.(type) {
+	// Table-driven test cases are only looked for when they are not
+	// counted with the other test literals
+	case *ast.File:
+		isTest := v.p.isTest(v.fileSet.Position(t.Pos()).Filename, t.Name.Name)
+		// Files are filtered by path before parsing, test packages
+		// can only be recognized here
+		if isTest && v.p.ignoreTests {
+			return nil
+		}
+		if !isTest || v.p.tableTests == TableTestsInclude {
+			return v
+		}
+		fv := *v
+		fv.tables = findTestTables(t)
+		return &fv
+
+	// Literals below a function declaration belong to it, function
+	// literals included
+	case *ast.FuncDecl:
+		name := funcDeclName(t)
+		if v.p.ignoredFunction(name) {
+			return nil
+		}
+		fv := *v
+		fv.function = InternString(name)
+		return &fv
+
 	// Scan for constants in an attempt to match strings with existing constants
 	case *ast.GenDecl:
-		if !v.p.matchConstant && !v.p.findDuplicates {
+		if !v.p.matchConstant && !v.p.findDuplicates && !v.p.testConstants {
 			return v
 		}
 		if t.Tok != token.CONST {
@@ -77,6 +110,12 @@ func (v *treeVisitor) Visit(node ast.Node) ast.Visitor {
 		if t.Op != token.EQL && t.Op != token.NEQ {
 			return v
 		}
+		if v.p.sentinelErrors && v.addErrorComparison(t) {
+			return v
+		}
+		if v.p.enums {
+			v.addEnumComparison(t)
+		}
 
 		var lit *ast.BasicLit
 		var ok bool
@@ -91,6 +130,12 @@ func (v *treeVisitor) Visit(node ast.Node) ast.Visitor {
 			v.addString(lit.Value, lit.Pos(), Binary)
 		}
 
+	// switch status { case "active": }
+	case *ast.SwitchStmt:
+		if v.p.enums && t.Tag != nil {
+			v.addEnumSwitch(t)
+		}
+
 	// case "foo":
 	case *ast.CaseClause:
 		for _, item := range t.List {
@@ -111,8 +156,22 @@ func (v *treeVisitor) Visit(node ast.Node) ast.Visitor {
 
 	// fn("http://")
 	case *ast.CallExpr:
+		if v.p.sentinelErrors && (v.addErrorMessage(t) || v.addErrorComparison(t)) {
+			return v
+		}
+		keyArg := -1
+		if v.p.configKeys {
+			keyArg = v.addConfigKey(t)
+		}
+		if v.p.contextKeys && keyArg < 0 {
+			keyArg = v.addContextKey(t)
+		}
 		if !v.shouldIgnoreCall(t) {
-			for _, item := range t.Args {
+			ignored := v.ignoredArgs(t)
+			for i, item := range t.Args {
+				if ignored[i] || i == keyArg {
+					continue
+				}
 				lit, ok := item.(*ast.BasicLit)
 				if ok && v.isSupported(lit.Kind) {
 					v.addString(lit.Value, lit.Pos(), Call)
@@ -122,17 +181,26 @@ func (v *treeVisitor) Visit(node ast.Node) ast.Visitor {
 
 	// []string{"foo"}, map[string]string{"k": "v"}, struct{A string}{A: "foo"}
 	case *ast.CompositeLit:
+		if v.tables[t] {
+			if v.p.tableTests == TableTestsExclude {
+				return nil
+			}
+			tv := *v
+			tv.table = t.Pos()
+			v = &tv
+		}
+		typeNames := v.compositeTypeNames(t)
 		for _, item := range t.Elts {
-			v.addCompositeLiteralElement(item)
+			v.addCompositeLiteralElement(item, typeNames)
 		}
 	}
 
 	return v
 }
 
-func (v *treeVisitor) addCompositeLiteralElement(node ast.Expr) {
+func (v *treeVisitor) addCompositeLiteralElement(node ast.Expr, typeNames []string) {
 	if lit, ok := node.(*ast.BasicLit); ok && v.isSupported(lit.Kind) {
-		v.addString(lit.Value, lit.Pos(), CompositeLit)
+		v.addCompositeString(lit, typeNames, "")
 		return
 	}
 
@@ -141,36 +209,184 @@ func (v *treeVisitor) addCompositeLiteralElement(node ast.Expr) {
 		return
 	}
 
+	var field string
+	if ident, ok := kv.Key.(*ast.Ident); ok {
+		field = ident.Name
+	}
+
 	if keyLit, ok := kv.Key.(*ast.BasicLit); ok && v.isSupported(keyLit.Kind) {
-		v.addString(keyLit.Value, keyLit.Pos(), CompositeLit)
+		v.addCompositeString(keyLit, typeNames, "")
 	}
 
 	if valueLit, ok := kv.Value.(*ast.BasicLit); ok && v.isSupported(valueLit.Kind) {
-		v.addString(valueLit.Value, valueLit.Pos(), CompositeLit)
+		v.addCompositeString(valueLit, typeNames, field)
+	}
+}
+
+// addCompositeString adds a literal found in a composite literal, applying
+// the composite literal rules for its type and field.
+func (v *treeVisitor) addCompositeString(lit *ast.BasicLit, typeNames []string, field string) {
+	if !v.p.hasCompositeRules() {
+		v.addString(lit.Value, lit.Pos(), CompositeLit)
+		return
+	}
+	ignored, included := v.p.compositeElement(typeNames, field)
+	switch {
+	case included:
+		v.recordString(lit.Value, lit.Pos(), CompositeLit)
+	case !ignored:
+		v.addString(lit.Value, lit.Pos(), CompositeLit)
 	}
 }
 
-// shouldIgnoreCall returns true if the call expression matches a function
-// name in the ignoreFunctions set. Supports direct calls (e.g., "println")
-// and one-level qualified calls (e.g., "slog.Info").
+// compositeTypeNames returns the names the type of a composite literal is
+// known by: as written (e.g. "cobra.Command") and, when type information is
+// available, as resolved (e.g. "github.com/spf13/cobra.Command"). It
+// returns nil when no composite literal rule is set.
+func (v *treeVisitor) compositeTypeNames(lit *ast.CompositeLit) []string {
+	if !v.p.hasCompositeRules() {
+		return nil
+	}
+	var names []string
+	if name := selectorPath(lit.Type); name != "" {
+		names = append(names, name)
+	}
+	if v.typeInfo != nil {
+		names = append(names, namedTypeNames(v.typeInfo.TypeOf(lit), v.packageName)...)
+	}
+	return names
+}
+
+// funcDeclName returns the name of a function declaration, prefixed with
+// the receiver type for methods (e.g. "Server.Start").
+func funcDeclName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	recv := fn.Recv.List[0].Type
+	for {
+		switch t := recv.(type) {
+		case *ast.StarExpr:
+			recv = t.X
+			continue
+		case *ast.IndexExpr:
+			recv = t.X
+			continue
+		case *ast.IndexListExpr:
+			recv = t.X
+			continue
+		case *ast.ParenExpr:
+			recv = t.X
+			continue
+		case *ast.Ident:
+			return t.Name + "." + fn.Name.Name
+		}
+		return fn.Name.Name
+	}
+}
+
+// shouldIgnoreCall returns true if any name of the callee matches the
+// ignored functions. See callNames for the names considered.
 func (v *treeVisitor) shouldIgnoreCall(call *ast.CallExpr) bool {
-	if len(v.p.ignoreFunctions) == 0 {
+	if !v.p.ignoresCalls() {
 		return false
 	}
-	var name string
-	switch fn := call.Fun.(type) {
+	return v.p.ignoredCall(v.callNames(call.Fun))
+}
+
+// ignoredArgs returns the indexes of the arguments of the call that are
+// ignored by the per-argument rules.
+func (v *treeVisitor) ignoredArgs(call *ast.CallExpr) map[int]bool {
+	if len(v.p.ignoreArgs) == 0 {
+		return nil
+	}
+	var sig *types.Signature
+	if v.typeInfo != nil {
+		sig, _ = v.typeInfo.TypeOf(call.Fun).(*types.Signature)
+	}
+	return v.p.ignoredArgs(v.callNames(call.Fun), sig, len(call.Args))
+}
+
+// callNames returns the names a callee is known by: the name as written
+// (e.g. "println", "slog.Info", "a.b.Log") and, when type information is
+// available, the resolved names. Functions resolve to "slog.Info" and
+// "log/slog.Info" whatever the import is named as, and methods to
+// "(*testing.T).Errorf" and "(testing.T).Errorf", after the receiver type of
+// the call and the type declaring the method.
+func (v *treeVisitor) callNames(fun ast.Expr) []string {
+	var names []string
+	if name := selectorPath(fun); name != "" {
+		names = append(names, name)
+	}
+	if v.typeInfo == nil {
+		return names
+	}
+
+	var sel *ast.Ident
+	switch fn := fun.(type) {
 	case *ast.Ident:
-		name = fn.Name
+		sel = fn
 	case *ast.SelectorExpr:
-		if ident, ok := fn.X.(*ast.Ident); ok {
-			name = ident.Name + "." + fn.Sel.Name
+		sel = fn.Sel
+		if x, ok := fn.X.(*ast.Ident); ok {
+			// Imports resolve even when their package could not be loaded
+			if pkgName, ok := v.typeInfo.Uses[x].(*types.PkgName); ok {
+				imported := pkgName.Imported()
+				names = append(names, imported.Name()+"."+sel.Name, imported.Path()+"."+sel.Name)
+			}
 		}
+		if selection, ok := v.typeInfo.Selections[fn]; ok && selection.Kind() == types.MethodVal {
+			if recv := receiverName(selection.Recv()); recv != "" {
+				names = append(names, "(*"+recv+")."+sel.Name, "("+recv+")."+sel.Name)
+			}
+		}
+	default:
+		return names
 	}
-	if name == "" {
-		return false
+
+	if fn, ok := v.typeInfo.Uses[sel].(*types.Func); ok {
+		names = append(names, fn.FullName())
+		if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() == nil && fn.Pkg() != nil {
+			names = append(names, fn.Pkg().Name()+"."+fn.Name())
+		}
 	}
-	_, found := v.p.ignoreFunctions[name]
-	return found
+	return names
+}
+
+// selectorPath returns the dotted name of an identifier or of a chain of
+// selectors, e.g. "a.b.Log", or "" for other expressions.
+func selectorPath(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		if x := selectorPath(e.X); x != "" {
+			return x + "." + e.Sel.Name
+		}
+	}
+	return ""
+}
+
+// receiverName returns the package-qualified name of a named receiver type,
+// e.g. "go.uber.org/zap.Logger", or "" for unnamed types.
+func receiverName(typ types.Type) string {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return ""
+	}
+	obj := named.Obj()
+	switch {
+	case obj.Pkg() == nil:
+		return obj.Name()
+	case obj.Pkg().Path() == "":
+		// Packages type-checked by ParseTree have no import path
+		return obj.Pkg().Name() + "." + obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
 }
 
 // addString adds a string in the map along with its position in the tree.
@@ -180,7 +396,12 @@ func (v *treeVisitor) addString(str string, pos token.Pos, typ Type) {
 	if ok && excluded {
 		return
 	}
+	v.recordString(str, pos, typ)
+}
 
+// recordString adds a string in the map whatever its context, provided it
+// passes the length, pattern and number range filters.
+func (v *treeVisitor) recordString(str string, pos token.Pos, typ Type) {
 	// Drop quotes if any
 	var unquotedStr string
 	if strings.HasPrefix(str, `"`) || strings.HasPrefix(str, "`") {
@@ -205,7 +426,7 @@ func (v *treeVisitor) addString(str string, pos token.Pos, typ Type) {
 	}
 
 	// Early length check
-	if len(unquotedStr) == 0 || utf8.RuneCountInString(unquotedStr) < v.p.minLength {
+	if len(unquotedStr) == 0 || utf8.RuneCountInString(unquotedStr) < v.p.minLengthFor(typ) {
 		return
 	}
 
@@ -234,14 +455,24 @@ func (v *treeVisitor) addString(str string, pos token.Pos, typ Type) {
 	v.p.stringMutex.Lock()
 	defer v.p.stringMutex.Unlock()
 
+	v.p.literals++
 	if _, exists := v.p.strs[internedStr]; !exists {
 		v.p.strs[internedStr] = make([]ExtendedPos, 0, v.p.minOccurrences)
 	}
 
-	v.p.strs[internedStr] = append(v.p.strs[internedStr], ExtendedPos{
+	v.p.strs[internedStr] = append(v.p.strs[internedStr], v.position(pos, typ))
+}
+
+// position returns the extended position of a literal found in the given
+// context.
+func (v *treeVisitor) position(pos token.Pos, typ Type) ExtendedPos {
+	return ExtendedPos{
 		packageName: InternString(v.packageName),
 		Position:    v.fileSet.Position(pos),
-	})
+		Context:     typ,
+		function:    v.function,
+		table:       v.table,
+	}
 }
 
 // addConst adds a const in the map along with its position in the tree.
@@ -267,8 +498,8 @@ func (v *treeVisitor) addConst(name string, val string, pos token.Pos) {
 		unquotedVal = val
 	}
 
-	// Skip constants with values that would be filtered anyway
-	if utf8.RuneCountInString(unquotedVal) < v.p.minLength {
+	// Skip constants with values that would be filtered in every context
+	if utf8.RuneCountInString(unquotedVal) < v.p.minConstLength() {
 		return
 	}
 
@@ -288,7 +519,7 @@ func (v *treeVisitor) addConst(name string, val string, pos token.Pos) {
 	// Collect the constant when it is the first with this value, when
 	// duplicate detection needs all of them, or when constant matching
 	// needs all of them to pick the best per scope.
-	if _, ok := v.p.consts[internedVal]; !ok || v.p.findDuplicates || v.p.matchConstant {
+	if _, ok := v.p.consts[internedVal]; !ok || v.p.findDuplicates || v.p.matchConstant || v.p.testConstants {
 		v.p.consts[internedVal] = append(v.p.consts[internedVal], ConstType{
 			Name:        internedName,
 			packageName: internedPkg,
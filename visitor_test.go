@@ -599,6 +599,16 @@ func example() {
 			ignoreFunctions: map[string]struct{}{"fmt.Errorf": {}},
 			expectStrings:   1, // only "keep" via fmt.Println
 		},
+		{
+			name: "nested selector ignored",
+			code: `package example
+func example() {
+	a.b.Log("msg")
+	a.b.Log("msg")
+}`,
+			ignoreFunctions: map[string]struct{}{"a.b.Log": {}},
+			expectStrings:   0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -641,3 +651,51 @@ func example() {
 		})
 	}
 }
+
+func TestTreeVisitor_EnclosingFunction(t *testing.T) {
+	code := `package example
+var pkgLevel = []string{"outside"}
+func plain() { _ = "plain" }
+func (s Server) value() { _ = "value" }
+func (s *Server) pointer() { _ = "pointer" }
+func (l *List[T]) generic() { _ = "generic" }
+func closure() {
+	f := func() { _ = "closure" }
+	f()
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse test code: %v", err)
+	}
+
+	p := &Parser{
+		minLength:       3,
+		minOccurrences:  1,
+		supportedTokens: []token.Token{token.STRING},
+		excludeTypes:    map[Type]bool{},
+		strs:            Strings{},
+		consts:          Constants{},
+		stringCount:     make(map[string]int),
+	}
+	ast.Walk(&treeVisitor{p: p, fileSet: fset, packageName: "example"}, f)
+
+	want := map[string]string{
+		"outside": "",
+		"plain":   "plain",
+		"value":   "Server.value",
+		"pointer": "Server.pointer",
+		"generic": "List.generic",
+		"closure": "closure",
+	}
+	for str, function := range want {
+		positions := p.strs[str]
+		if len(positions) != 1 {
+			t.Errorf("%q: got %d positions, want 1", str, len(positions))
+			continue
+		}
+		if got := positions[0].Function(); got != function {
+			t.Errorf("%q: Function() = %q, want %q", str, got, function)
+		}
+	}
+}